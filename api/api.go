@@ -0,0 +1,11 @@
+package api
+
+import (
+	_ "embed"
+)
+
+// OpenAPI is the OpenAPI 3 document of the HTTP API. Keep it in sync with
+// the routes registered in cmd/lyrics-library.
+//
+//go:embed openapi.json
+var OpenAPI []byte
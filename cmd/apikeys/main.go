@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"lyrics-library/internal/config"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/service/auth"
+	"lyrics-library/internal/storage/postgres"
+)
+
+const timeout = 10 * time.Second
+
+func main() {
+	var (
+		configPath string
+		action     string
+		name       string
+		scopes     string
+		id         string
+	)
+
+	flag.StringVar(&configPath, "config", "", "Path to the config file, defaults to CONFIG_PATH")
+	flag.StringVar(&action, "action", "", "Action to perform: create, list or revoke")
+	flag.StringVar(&name, "name", "", "Name of the key to create, e.g. the client it is given to")
+	flag.StringVar(&scopes, "scopes", string(models.ScopeRead), "Comma-separated scopes of the key to create: read, write, admin")
+	flag.StringVar(&id, "id", "", "ID of the key to revoke")
+
+	flag.Parse()
+
+	cfg := config.MustLoadDB(configPath)
+
+	storage, err := postgres.New(cfg.URL(), nil)
+	if err != nil {
+		panic(err)
+	}
+	defer func() { _ = storage.Close(context.Background()) }()
+
+	service := auth.New(slogdiscard.NewDiscardLogger(), storage)
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	switch action {
+	case "create":
+		err = createKey(ctx, service, name, scopes)
+	case "list":
+		err = listKeys(ctx, service)
+	case "revoke":
+		err = revokeKey(ctx, service, id)
+	default:
+		err = fmt.Errorf("unknown action %q, expected create, list or revoke", action)
+	}
+
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func createKey(ctx context.Context, service *auth.AuthService, name, scopesArg string) error {
+	if name == "" {
+		return fmt.Errorf("name is required")
+	}
+
+	var scopes []models.Scope
+	for _, s := range strings.Split(scopesArg, ",") {
+		scope, ok := models.ParseScope(strings.TrimSpace(s))
+		if !ok {
+			return fmt.Errorf("unknown scope %q", s)
+		}
+
+		scopes = append(scopes, scope)
+	}
+
+	apiKey, key, err := service.CreateKey(ctx, name, scopes)
+	if err != nil {
+		return fmt.Errorf("failed to create key: %w", err)
+	}
+
+	fmt.Printf("Created key %s (%s) with scopes %s\n", apiKey.ID, apiKey.Name, scopesArg)
+	fmt.Println("Store it now, it can't be shown again:")
+	fmt.Println(key)
+
+	return nil
+}
+
+func listKeys(ctx context.Context, service *auth.AuthService) error {
+	keys, err := service.Keys(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list keys: %w", err)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+
+	fmt.Fprintln(w, "ID\tNAME\tPREFIX\tSCOPES\tCREATED\tREVOKED")
+
+	for _, key := range keys {
+		scopes := make([]string, 0, len(key.Scopes))
+		for _, scope := range key.Scopes {
+			scopes = append(scopes, string(scope))
+		}
+
+		revoked := "-"
+		if key.RevokedAt != nil {
+			revoked = key.RevokedAt.Format(time.DateTime)
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n",
+			key.ID, key.Name, key.Prefix, strings.Join(scopes, ","),
+			key.CreatedAt.Format(time.DateTime), revoked)
+	}
+
+	return w.Flush()
+}
+
+func revokeKey(ctx context.Context, service *auth.AuthService, id string) error {
+	if id == "" {
+		return fmt.Errorf("id is required")
+	}
+
+	if err := service.RevokeKey(ctx, id); err != nil {
+		return fmt.Errorf("failed to revoke key: %w", err)
+	}
+
+	fmt.Printf("Revoked key %s\n", id)
+
+	return nil
+}
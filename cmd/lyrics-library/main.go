@@ -13,16 +13,46 @@ import (
 	"github.com/go-chi/chi/v5"
 	"github.com/go-chi/chi/v5/middleware"
 
+	"lyrics-library/api"
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/client/chain"
+	"lyrics-library/internal/client/libretranslate"
+	"lyrics-library/internal/client/lrclib"
 	"lyrics-library/internal/client/lyricsovh"
+	"lyrics-library/internal/client/quota"
 	"lyrics-library/internal/client/yandex"
 	"lyrics-library/internal/config"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/docs"
+	healthHandler "lyrics-library/internal/http-server/handler/health"
+	jobget "lyrics-library/internal/http-server/handler/jobs/get"
+	"lyrics-library/internal/http-server/handler/lyrics/byuuid"
 	del "lyrics-library/internal/http-server/handler/lyrics/delete"
 	"lyrics-library/internal/http-server/handler/lyrics/get"
 	"lyrics-library/internal/http-server/handler/lyrics/save"
-	healthchecker "lyrics-library/internal/http-server/middleware/health-checker"
+	"lyrics-library/internal/http-server/handler/lyrics/search"
+	"lyrics-library/internal/http-server/handler/lyrics/update"
+	"lyrics-library/internal/http-server/handler/lyrics/uploadlrc"
+	"lyrics-library/internal/http-server/middleware/accesslog"
+	mwAuth "lyrics-library/internal/http-server/middleware/auth"
+	mwMetrics "lyrics-library/internal/http-server/middleware/metrics"
+	"lyrics-library/internal/http-server/middleware/ratelimit"
+	"lyrics-library/internal/http-server/middleware/realip"
+	"lyrics-library/internal/http-server/middleware/requestid"
+	mwTracing "lyrics-library/internal/http-server/middleware/tracing"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/background"
 	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/logger/slogctx"
 	"lyrics-library/internal/lib/logger/slogpretty"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/tracing"
+	"lyrics-library/internal/service/auth"
+	"lyrics-library/internal/service/health"
+	"lyrics-library/internal/service/job"
 	"lyrics-library/internal/service/track"
+	"lyrics-library/internal/storage/guard"
+	"lyrics-library/internal/storage/memory"
 	"lyrics-library/internal/storage/postgres"
 	"lyrics-library/internal/storage/redis"
 )
@@ -31,7 +61,8 @@ const (
 	envLocal = "local"
 	envProd  = "prod"
 
-	shutdownTimeout = 15 * time.Second
+	shutdownTimeout   = 15 * time.Second
+	cacheWriteTimeout = 5 * time.Second
 )
 
 func main() {
@@ -39,6 +70,8 @@ func main() {
 
 	log := setupLogger(cfg.Env)
 
+	appMetrics := metrics.New()
+
 	ctx, cancel := signal.NotifyContext(
 		context.Background(),
 		os.Interrupt,
@@ -47,50 +80,155 @@ func main() {
 	)
 	defer cancel()
 
-	dbURL := connURL(cfg)
-
-	log.Debug("Connecting to database", slog.String("url", dbURL))
-
-	storage, err := postgres.New(dbURL)
+	shutdownTracing, err := tracing.Setup(ctx, tracing.Config{
+		Exporter:     cfg.Tracing.Exporter,
+		ServiceName:  cfg.Tracing.ServiceName,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		OTLPInsecure: cfg.Tracing.OTLPInsecure,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
 	if err != nil {
 		panic(err)
 	}
 
-	redisHost := redisHost(cfg)
+	storage, cache, limiter := setupStorage(ctx, log, cfg, appMetrics)
 
-	log.Debug("Connecting to redis", slog.String("host", redisHost))
+	lyricsClient, lyricsProbes := lyricsProvider(log, cfg, appMetrics)
+	translateClient, translatorProbe := lyricsTranslator(log, cfg, appMetrics)
 
-	redisCache, err := redis.New(redisHost, cfg.Redis.Password)
-	if err != nil {
-		panic(err)
+	if cfg.Translation.DailyQuotaChars > 0 {
+		translateClient = quota.New(log, translateClient, limiter, cfg.Translation.DailyQuotaChars)
 	}
 
-	lyricsClient := lyricsovh.New(log)
-	translateClient := yandex.New(log, cfg.YandexTranslatorAPI.Key)
+	cacheWriters := background.New(cacheWriteTimeout)
 
 	trackService := track.New(
 		log,
 		lyricsClient,
 		translateClient,
 		storage,
-		redisCache,
+		cache,
+		cfg.Translation.DefaultTargetLangs,
+		cacheWriters,
+	)
+
+	jobService := job.New(
+		log,
+		storage,
+		trackService,
+		cfg.Jobs.Workers,
+		cfg.Jobs.PollInterval,
+		cfg.Jobs.Timeout,
+		cfg.Jobs.MaxAttempts,
+		cfg.Jobs.RetryDelay,
+		cfg.Jobs.Retention,
 	)
 
+	var probes []health.Probe
+
+	if cfg.Storage.Backend == config.StorageBackendPostgres {
+		probes = append(probes,
+			health.Probe{Name: "postgres", Pinger: storage, Required: true},
+			health.Probe{Name: "redis", Pinger: cache},
+		)
+	}
+
+	if cfg.Health.ProbeUpstreams {
+		probes = append(probes, lyricsProbes...)
+		probes = append(probes, translatorProbe)
+	}
+
+	healthService := health.New(log, cfg.Health.Interval, cfg.Health.Timeout, probes...)
+
+	go healthService.Run(ctx)
+
+	workersDone := make(chan struct{})
+	go func() {
+		defer close(workersDone)
+
+		jobService.Run(ctx)
+	}()
+
+	trustedProxies, err := realip.ParsePrefixes(cfg.HTTPServer.TrustedProxies)
+	if err != nil {
+		panic(err)
+	}
+
 	router := chi.NewRouter()
 
+	router.Use(realip.New(trustedProxies))
+	router.Use(requestid.New())
+	// The access log runs inside the server span, so its line carries the
+	// trace id.
+	router.Use(mwTracing.New())
+	router.Use(accesslog.New(log))
 	router.Use(middleware.Recoverer)
+	router.Use(mwMetrics.New(appMetrics))
 	router.Use(middleware.URLFormat)
-	router.Use(healthchecker.New(log, storage))
 
-	router.Route("/lyrics", func(r chi.Router) {
-		r.Post("/", save.New(ctx, log, trackService))
-		r.Get("/", get.New(ctx, log, trackService, trackService))
-		r.Delete("/{uuid}", del.New(ctx, log, trackService))
+	router.NotFound(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, problem.New(http.StatusNotFound, problem.CodeNotFound, "no such endpoint"))
+	})
+	router.MethodNotAllowed(func(w http.ResponseWriter, r *http.Request) {
+		problem.Write(w, r, problem.New(http.StatusMethodNotAllowed, problem.CodeMethodNotAllowed,
+			r.Method+" is not allowed here",
+		))
+	})
+
+	authService := auth.New(log, storage)
+
+	requireScope := func(scope models.Scope) func(http.Handler) http.Handler {
+		if !cfg.Auth.Enabled {
+			return func(next http.Handler) http.Handler { return next }
+		}
+
+		return mwAuth.RequireScope(scope)
+	}
+
+	router.Group(func(r chi.Router) {
+		if cfg.Auth.Enabled {
+			if cfg.RateLimit.Enabled {
+				r.Use(ratelimit.New(log, limiter, cfg.RateLimit.IPRPS, cfg.RateLimit.IPBurst))
+			}
+
+			r.Use(mwAuth.New(log, authService))
+		} else {
+			log.Warn("api key authentication is disabled")
+		}
+
+		if cfg.RateLimit.Enabled {
+			r.Use(ratelimit.New(log, limiter, cfg.RateLimit.RPS, cfg.RateLimit.Burst))
+		}
+
+		read := requireScope(models.ScopeRead)
+		write := requireScope(models.ScopeWrite)
+		admin := requireScope(models.ScopeAdmin)
+
+		r.Route("/lyrics", func(r chi.Router) {
+			r.With(write).Post("/", save.New(log, trackService, jobService))
+			r.With(read).Get("/", get.New(log, trackService, trackService))
+			r.With(read).Get("/search", search.New(log, trackService))
+			r.With(read).Get("/{uuid}", byuuid.New(log, trackService))
+			r.With(write).Patch("/{uuid}", update.New(log, trackService))
+			r.With(admin).Delete("/{uuid}", del.New(log, trackService))
+			r.With(write).Put("/{uuid}/lrc", uploadlrc.New(log, trackService))
+		})
+
+		r.With(read).Get("/jobs/{id}", jobget.New(log, jobService))
 	})
 
+	// middleware.URLFormat strips the extension, so this serves /openapi.json.
+	router.Get("/openapi", docs.Spec(api.OpenAPI))
+	router.Get("/docs", docs.Page())
+
+	router.Handle("/metrics", appMetrics.Handler())
+
+	router.Get("/healthz", healthHandler.Live())
+	router.Get("/readyz", healthHandler.Ready(healthService))
+
 	srv := &http.Server{
 		Addr:         cfg.HTTPServer.Address,
-		Handler:      router,
+		Handler:      tracing.Handler(router),
 		ReadTimeout:  cfg.HTTPServer.Timeout,
 		WriteTimeout: cfg.HTTPServer.Timeout,
 		IdleTimeout:  cfg.HTTPServer.IdleTimeout,
@@ -120,17 +258,98 @@ func main() {
 		log.Error("failed to shutdown server", sl.Err(err))
 	}
 
+	select {
+	case <-workersDone:
+	case <-shutdownCtx.Done():
+		log.Error("job workers did not stop in time")
+	}
+
+	if err := cacheWriters.Shutdown(shutdownCtx); err != nil {
+		log.Error("cache writes did not finish in time", sl.Err(err))
+	}
+
 	if err := storage.Close(shutdownCtx); err != nil {
 		log.Error("failed to close storage", sl.Err(err))
 	}
 
-	if err := redisCache.Close(shutdownCtx); err != nil {
-		log.Error("failed to close redis", sl.Err(err))
+	if err := cache.Close(shutdownCtx); err != nil {
+		log.Error("failed to close cache", sl.Err(err))
+	}
+
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		log.Error("failed to flush traces", sl.Err(err))
 	}
 
 	log.Info("service stopped gracefully")
 }
 
+type appStorage interface {
+	track.TrackStorage
+	job.JobStorage
+	auth.KeyStorage
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type appCache interface {
+	track.TrackCache
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type appLimiter interface {
+	ratelimit.Limiter
+	quota.Counter
+}
+
+func setupStorage(
+	ctx context.Context,
+	log *slog.Logger,
+	cfg *config.Config,
+	m *metrics.Metrics,
+) (appStorage, appCache, appLimiter) {
+	if cfg.Storage.Backend == config.StorageBackendMemory {
+		log.Info("using in-memory storage")
+
+		return memory.New(), memory.NewCache(
+			cfg.Cache.Size,
+			cfg.Cache.TrackTTL,
+			cfg.Cache.ArtistTracksTTL,
+			m,
+		), memory.NewLimiter()
+	}
+
+	dbURL := cfg.DB.URL()
+
+	log.Debug("Connecting to database", slog.String("url", dbURL))
+
+	storage, err := postgres.New(dbURL, m)
+	if err != nil {
+		panic(err)
+	}
+
+	redisHost := redisHost(cfg)
+
+	log.Debug("Connecting to redis", slog.String("host", redisHost))
+
+	redisCache := guard.New(
+		log,
+		redis.New(
+			redisHost,
+			cfg.Redis.Password,
+			cfg.Cache.TrackTTL,
+			cfg.Cache.ArtistTracksTTL,
+			m,
+		),
+		cfg.Cache.BreakerThreshold,
+		cfg.Cache.BreakerCooldown,
+	)
+
+	go redisCache.Run(ctx)
+
+	return storage, redisCache, redisCache
+}
+
 func setupLogger(env string) *slog.Logger {
 	var log *slog.Logger
 
@@ -138,9 +357,9 @@ func setupLogger(env string) *slog.Logger {
 	case envLocal:
 		log = setupPrettyLogger()
 	case envProd:
-		log = slog.New(
+		log = slog.New(slogctx.NewHandler(
 			slog.NewJSONHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelInfo}),
-		)
+		))
 	}
 
 	return log
@@ -155,12 +374,83 @@ func setupPrettyLogger() *slog.Logger {
 
 	handler := opts.NewPrettyHandler(os.Stdout)
 
-	return slog.New(handler)
+	return slog.New(slogctx.NewHandler(handler))
+}
+
+func lyricsProvider(
+	log *slog.Logger,
+	cfg *config.Config,
+	m *metrics.Metrics,
+) (*chain.Chain, []health.Probe) {
+	providers := make([]chain.LyricsProvider, 0, len(cfg.Lyrics.Providers))
+	probes := make([]health.Probe, 0, len(cfg.Lyrics.Providers))
+
+	for _, name := range cfg.Lyrics.Providers {
+		var provider interface {
+			chain.LyricsProvider
+			health.Pinger
+		}
+
+		switch name {
+		case lyricsovh.Name:
+			provider = lyricsovh.New(log, transportConfig(cfg), m)
+		case lrclib.Name:
+			provider = lrclib.New(log, transportConfig(cfg), m)
+		default:
+			panic("unknown lyrics provider: " + name)
+		}
+
+		providers = append(providers, provider)
+		probes = append(probes, health.Probe{Name: name, Pinger: provider})
+	}
+
+	if len(providers) == 0 {
+		panic("at least one lyrics provider is required")
+	}
+
+	return chain.New(log, providers...), probes
+}
+
+func lyricsTranslator(
+	log *slog.Logger,
+	cfg *config.Config,
+	m *metrics.Metrics,
+) (track.LyricsTranslator, health.Probe) {
+	switch cfg.Translation.Backend {
+	case config.TranslationBackendLibreTranslate:
+		log.Info("using libretranslate", slog.String("url", cfg.LibreTranslate.URL))
+
+		translator := libretranslate.New(
+			log,
+			cfg.LibreTranslate.URL,
+			cfg.LibreTranslate.APIKey,
+			cfg.Translation.MaxBatchChars,
+			transportConfig(cfg),
+			m,
+		)
+
+		return translator, health.Probe{Name: libretranslate.Name, Pinger: translator}
+	default:
+		translator := yandex.New(
+			log,
+			cfg.YandexTranslatorAPI.Key,
+			cfg.Translation.MaxBatchChars,
+			transportConfig(cfg),
+			m,
+		)
+
+		return translator, health.Probe{Name: yandex.Name, Pinger: translator}
+	}
 }
 
-func connURL(cfg *config.Config) string {
-	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.DB.User, cfg.DB.Password, cfg.DB.Host, cfg.DB.Port, cfg.DB.Name)
+func transportConfig(cfg *config.Config) apiClient.TransportConfig {
+	return apiClient.TransportConfig{
+		MaxRetries:       cfg.Upstream.MaxRetries,
+		BaseDelay:        cfg.Upstream.RetryBaseDelay,
+		MaxDelay:         cfg.Upstream.RetryMaxDelay,
+		BreakerThreshold: cfg.Upstream.BreakerThreshold,
+		BreakerCooldown:  cfg.Upstream.BreakerCooldown,
+	}
 }
 
 func redisHost(cfg *config.Config) string {
@@ -1,6 +1,8 @@
 package main
 
 import (
+	"context"
+	"database/sql"
 	"errors"
 	"flag"
 	"fmt"
@@ -14,30 +16,26 @@ import (
 
 func main() {
 	var (
+		configPath     string
 		migrationsPath string
 		action         string
 		forceVersion   int
 	)
 
+	flag.StringVar(&configPath, "config", "", "Path to the config file, defaults to CONFIG_PATH")
 	flag.StringVar(&migrationsPath, "migrations-path", "", "Path to the migrations folder")
-	flag.StringVar(&action, "action", "", "Action to perform: up (apply migrations) or down (rollback migrations)")
+	flag.StringVar(&action, "action", "", "Action to perform: up (apply migrations), down (rollback migrations) or rekey (recompute track identity keys)")
 	flag.IntVar(&forceVersion, "force-version", 0, "Force version to rollback")
 
 	flag.Parse()
 
-	cfg := config.MustLoad()
+	cfg := config.MustLoadDB(configPath)
 
 	if migrationsPath == "" {
 		panic("migrations path is required")
 	}
 
-	dbURL := fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
-		cfg.DB.User,
-		cfg.DB.Password,
-		cfg.DB.Host,
-		cfg.DB.Port,
-		cfg.DB.Name,
-	)
+	dbURL := cfg.URL()
 
 	fmt.Println(dbURL)
 
@@ -57,13 +55,42 @@ func main() {
 
 	switch action {
 	case "up":
+		before := currentVersion(m)
+
 		if err := applyMigrations(m); err != nil {
 			panic(err)
 		}
+
+		if before < identityMigration && currentVersion(m) >= identityMigration {
+			rekey(dbURL)
+		}
 	case "down":
 		if err := rollbackMigrations(m); err != nil {
 			panic(err)
 		}
+	case "rekey":
+		rekey(dbURL)
+	}
+}
+
+func currentVersion(m *migrate.Migrate) uint {
+	version, _, err := m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		panic(err)
+	}
+
+	return version
+}
+
+func rekey(dbURL string) {
+	db, err := sql.Open("postgres", dbURL)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	if err := rekeyTracks(context.Background(), db); err != nil {
+		panic(err)
 	}
 }
 
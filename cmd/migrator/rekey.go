@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/lib/pq"
+
+	"lyrics-library/internal/lib/identity"
+)
+
+// identityMigration is the migration that introduced identity keys. Its
+// backfill approximates identity.TrackKey in SQL, so the keys are computed
+// again in Go once it's applied.
+const identityMigration = 5
+
+type staleKey struct {
+	id            int64
+	artist, title string
+	key           string
+}
+
+// rekeyTracks sets identity_key of every song to identity.TrackKey. A song
+// whose key is taken by another song is left as it is and reported, so
+// duplicates are merged by hand instead of being dropped.
+func rekeyTracks(ctx context.Context, db *sql.DB) error {
+	stale, err := staleKeys(ctx, db)
+	if err != nil {
+		return fmt.Errorf("failed to read identity keys: %w", err)
+	}
+
+	// A key may be freed by a song updated later, so the conflicting songs
+	// are retried until a pass makes no progress.
+	for len(stale) > 0 {
+		var conflicts []staleKey
+
+		for _, song := range stale {
+			_, err := db.ExecContext(ctx, `UPDATE songs SET identity_key = $1 WHERE id = $2`, song.key, song.id)
+
+			var pqErr *pq.Error
+			if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+				conflicts = append(conflicts, song)
+
+				continue
+			}
+
+			if err != nil {
+				return fmt.Errorf("failed to update identity key of song %d: %w", song.id, err)
+			}
+		}
+
+		if len(conflicts) == len(stale) {
+			break
+		}
+
+		stale = conflicts
+	}
+
+	fmt.Printf("Identity keys updated, %d conflicting songs left\n", len(stale))
+
+	for _, song := range stale {
+		var existing int64
+
+		err := db.QueryRowContext(ctx, `SELECT id FROM songs WHERE identity_key = $1`, song.key).Scan(&existing)
+		if err != nil {
+			return fmt.Errorf("failed to find the duplicate of song %d: %w", song.id, err)
+		}
+
+		fmt.Printf("Song %d (%q - %q) duplicates song %d, merge or delete one of them and run -action=rekey again\n",
+			song.id, song.artist, song.title, existing)
+	}
+
+	return nil
+}
+
+func staleKeys(ctx context.Context, db *sql.DB) ([]staleKey, error) {
+	rows, err := db.QueryContext(ctx, `SELECT id, artist, title, identity_key FROM songs ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stale []staleKey
+	for rows.Next() {
+		var (
+			song    staleKey
+			current string
+		)
+
+		if err := rows.Scan(&song.id, &song.artist, &song.title, &current); err != nil {
+			return nil, err
+		}
+
+		song.key = identity.TrackKey(song.artist, song.title)
+		if song.key != current {
+			stale = append(stale, song)
+		}
+	}
+
+	return stale, rows.Err()
+}
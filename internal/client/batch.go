@@ -0,0 +1,112 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"unicode/utf8"
+)
+
+var (
+	ErrTranslationMisaligned = fmt.Errorf("%w: translated lines don't match source lines", ErrFailedTranslateLyrics)
+	ErrLineTooLong           = fmt.Errorf("%w: line exceeds translation batch budget", ErrFailedTranslateLyrics)
+)
+
+// BatchLines splits lines into consecutive batches whose total length in
+// characters doesn't exceed budget. Lines are never split, so a single line
+// longer than budget is an error.
+func BatchLines(lines []string, budget int) ([][]string, error) {
+	var (
+		batches [][]string
+		batch   []string
+		size    int
+	)
+
+	for i, line := range lines {
+		n := utf8.RuneCountInString(line)
+		if n > budget {
+			return nil, fmt.Errorf("%w: line %d has %d characters, budget is %d",
+				ErrLineTooLong, i+1, n, budget)
+		}
+
+		if size+n > budget && len(batch) > 0 {
+			batches = append(batches, batch)
+			batch, size = nil, 0
+		}
+
+		batch = append(batch, line)
+		size += n
+	}
+
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+
+	return batches, nil
+}
+
+// AlignTranslation checks that a translator returned exactly one line for
+// every source line. Line breaks inside a translated line are flattened so
+// the result stays line-aligned.
+func AlignTranslation(source, translated []string) ([]string, error) {
+	if len(source) != len(translated) {
+		return nil, fmt.Errorf("%w: sent %d lines, got %d",
+			ErrTranslationMisaligned, len(source), len(translated))
+	}
+
+	result := make([]string, len(translated))
+	for i, line := range translated {
+		result[i] = strings.Join(strings.Fields(line), " ")
+	}
+
+	return result, nil
+}
+
+// TranslateFunc translates one batch of lines and returns the translated
+// texts in the order of the lines.
+type TranslateFunc func(ctx context.Context, lines []string) ([]string, error)
+
+// TranslateInBatches splits lines into batches of at most budget
+// characters, translates them one after another, each within
+// RequestTimeout, and joins the aligned results.
+func TranslateInBatches(
+	ctx context.Context,
+	log *slog.Logger,
+	lines []string,
+	budget int,
+	translate TranslateFunc,
+) ([]string, error) {
+	batches, err := BatchLines(lines, budget)
+	if err != nil {
+		return nil, err
+	}
+
+	translated := make([]string, 0, len(lines))
+	for i, batch := range batches {
+		texts, err := translateBatch(ctx, batch, translate)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to translate batch", slog.Int("batch", i+1), slog.Int("batches", len(batches)))
+
+			return nil, err
+		}
+
+		translated = append(translated, texts...)
+	}
+
+	log.InfoContext(ctx, "lyrics translated successfully", slog.Int("batches", len(batches)))
+
+	return translated, nil
+}
+
+func translateBatch(ctx context.Context, batch []string, translate TranslateFunc) ([]string, error) {
+	ctx, cancel := context.WithTimeout(ctx, RequestTimeout)
+	defer cancel()
+
+	texts, err := translate(ctx, batch)
+	if err != nil {
+		return nil, err
+	}
+
+	return AlignTranslation(batch, texts)
+}
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+func TestBatchLines(t *testing.T) {
+	tests := []struct {
+		name    string
+		lines   []string
+		budget  int
+		want    [][]string
+		wantErr error
+	}{
+		{
+			name:   "everything fits",
+			lines:  []string{"ab", "cd"},
+			budget: 10,
+			want:   [][]string{{"ab", "cd"}},
+		},
+		{
+			name:   "split at the budget",
+			lines:  []string{"ab", "cd", "ef"},
+			budget: 4,
+			want:   [][]string{{"ab", "cd"}, {"ef"}},
+		},
+		{
+			name:   "characters, not bytes",
+			lines:  []string{"привет", "мир"},
+			budget: 9,
+			want:   [][]string{{"привет", "мир"}},
+		},
+		{
+			name:    "line over the budget",
+			lines:   []string{"ab", "abcdef"},
+			budget:  4,
+			wantErr: ErrLineTooLong,
+		},
+		{
+			name:   "no lines",
+			budget: 4,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := BatchLines(tt.lines, tt.budget)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("BatchLines() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("BatchLines() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTranslateInBatches(t *testing.T) {
+	var batches [][]string
+
+	got, err := TranslateInBatches(context.Background(), slogdiscard.NewDiscardLogger(),
+		[]string{"one", "two", "three"}, 6,
+		func(ctx context.Context, lines []string) ([]string, error) {
+			if _, ok := ctx.Deadline(); !ok {
+				t.Error("batch is translated without a deadline")
+			}
+
+			batches = append(batches, lines)
+
+			texts := make([]string, len(lines))
+			for i, line := range lines {
+				texts[i] = strings.ToUpper(line) + "\n  !"
+			}
+
+			return texts, nil
+		},
+	)
+	if err != nil {
+		t.Fatalf("TranslateInBatches() error = %v", err)
+	}
+
+	want := []string{"ONE !", "TWO !", "THREE !"}
+	if !slices.Equal(got, want) {
+		t.Errorf("TranslateInBatches() = %q, want %q", got, want)
+	}
+
+	if len(batches) != 2 {
+		t.Errorf("got %d batches, want 2", len(batches))
+	}
+}
+
+func TestTranslateInBatchesStopsOnError(t *testing.T) {
+	errBoom := errors.New("boom")
+
+	calls := 0
+
+	_, err := TranslateInBatches(context.Background(), slogdiscard.NewDiscardLogger(),
+		[]string{"one", "two", "three"}, 5,
+		func(ctx context.Context, lines []string) ([]string, error) {
+			calls++
+
+			if calls == 2 {
+				return nil, errBoom
+			}
+
+			return lines, nil
+		},
+	)
+	if !errors.Is(err, errBoom) {
+		t.Fatalf("TranslateInBatches() error = %v, want %v", err, errBoom)
+	}
+
+	if calls != 2 {
+		t.Errorf("translate called %d times, want 2", calls)
+	}
+}
+
+func TestTranslateInBatchesMisaligned(t *testing.T) {
+	_, err := TranslateInBatches(context.Background(), slogdiscard.NewDiscardLogger(),
+		[]string{"one", "two"}, 10,
+		func(ctx context.Context, lines []string) ([]string, error) {
+			return []string{"one two"}, nil
+		},
+	)
+	if !errors.Is(err, ErrTranslationMisaligned) {
+		t.Fatalf("TranslateInBatches() error = %v, want %v", err, ErrTranslationMisaligned)
+	}
+
+	if !errors.Is(err, ErrFailedTranslateLyrics) {
+		t.Errorf("TranslateInBatches() error = %v, want it to match %v", err, ErrFailedTranslateLyrics)
+	}
+}
@@ -0,0 +1,69 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+)
+
+type LyricsProvider interface {
+	Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error)
+}
+
+// Chain asks providers in order and returns the first lyrics found.
+type Chain struct {
+	log       *slog.Logger
+	providers []LyricsProvider
+}
+
+func New(log *slog.Logger, providers ...LyricsProvider) *Chain {
+	return &Chain{
+		log:       log,
+		providers: providers,
+	}
+}
+
+func (c *Chain) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+	const op = "service.api.chain.Lyrics"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := c.log.With(slog.String("op", op))
+
+	var lastErr error
+	for _, provider := range c.providers {
+		lyrics, err := provider.Lyrics(ctx, artist, title)
+		if err == nil {
+			log.InfoContext(ctx, "lyrics found", slog.String("source", lyrics.Source))
+
+			return lyrics, nil
+		}
+
+		if ctx.Err() != nil {
+			return nil, fmt.Errorf("%s: %w", op, ctx.Err())
+		}
+
+		if errors.Is(err, apiClient.ErrLyricsNotFound) {
+			log.DebugContext(ctx, "provider has no lyrics", sl.Err(err))
+
+			continue
+		}
+
+		log.WarnContext(ctx, "provider failed, trying next", sl.Err(err))
+
+		lastErr = err
+	}
+
+	if lastErr != nil {
+		return nil, fmt.Errorf("%s: %w", op, lastErr)
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, apiClient.ErrLyricsNotFound)
+}
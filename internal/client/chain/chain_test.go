@@ -0,0 +1,145 @@
+package chain_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/client/chain"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+var errUpstream = errors.New("unexpected status: 502 Bad Gateway")
+
+// provider answers with lyrics from its name as the source, or with err,
+// and records its name in calls.
+type provider struct {
+	name  string
+	err   error
+	calls *[]string
+}
+
+func (p provider) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+	*p.calls = append(*p.calls, p.name)
+
+	if p.err != nil {
+		return nil, p.err
+	}
+
+	return &models.Lyrics{Source: p.name, Lines: []string{"line"}}, nil
+}
+
+func TestChainLyrics(t *testing.T) {
+	tests := []struct {
+		name       string
+		errs       []error
+		wantSource string
+		wantCalls  []string
+		wantErr    error
+	}{
+		{
+			name:       "first provider wins",
+			errs:       []error{nil, nil},
+			wantSource: "p0",
+			wantCalls:  []string{"p0"},
+		},
+		{
+			name:       "not found moves on",
+			errs:       []error{apiClient.ErrLyricsNotFound, nil},
+			wantSource: "p1",
+			wantCalls:  []string{"p0", "p1"},
+		},
+		{
+			name:       "failure moves on",
+			errs:       []error{errUpstream, apiClient.ErrLyricsNotFound, nil},
+			wantSource: "p2",
+			wantCalls:  []string{"p0", "p1", "p2"},
+		},
+		{
+			name:      "all not found",
+			errs:      []error{apiClient.ErrLyricsNotFound, apiClient.ErrLyricsNotFound},
+			wantCalls: []string{"p0", "p1"},
+			wantErr:   apiClient.ErrLyricsNotFound,
+		},
+		{
+			name:      "real error wins over not found",
+			errs:      []error{errUpstream, apiClient.ErrLyricsNotFound},
+			wantCalls: []string{"p0", "p1"},
+			wantErr:   errUpstream,
+		},
+		{
+			name:    "no providers",
+			wantErr: apiClient.ErrLyricsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls []string
+
+			providers := make([]chain.LyricsProvider, 0, len(tt.errs))
+			for i, err := range tt.errs {
+				providers = append(providers, provider{name: fmt.Sprintf("p%d", i), err: err, calls: &calls})
+			}
+
+			lyrics, err := chain.New(slogdiscard.NewDiscardLogger(), providers...).
+				Lyrics(context.Background(), "artist", "title")
+
+			if !slices.Equal(calls, tt.wantCalls) {
+				t.Errorf("providers called = %q, want %q", calls, tt.wantCalls)
+			}
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Errorf("Lyrics() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Lyrics() error = %v", err)
+			}
+
+			if lyrics.Source != tt.wantSource {
+				t.Errorf("Lyrics() source = %q, want %q", lyrics.Source, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestChainLyricsCancelled(t *testing.T) {
+	var calls []string
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	cancelling := providerFunc(func(ctx context.Context) error {
+		calls = append(calls, "p0")
+		cancel()
+
+		return ctx.Err()
+	})
+
+	_, err := chain.New(slogdiscard.NewDiscardLogger(),
+		cancelling,
+		provider{name: "p1", calls: &calls},
+	).Lyrics(ctx, "artist", "title")
+
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("Lyrics() error = %v, want %v", err, context.Canceled)
+	}
+
+	if !slices.Equal(calls, []string{"p0"}) {
+		t.Errorf("providers called = %q, want [p0]", calls)
+	}
+}
+
+type providerFunc func(ctx context.Context) error
+
+func (f providerFunc) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+	return nil, f(ctx)
+}
@@ -1,7 +1,11 @@
 package client
 
 import (
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"net/http"
 	"strings"
 	"time"
 )
@@ -9,8 +13,25 @@ import (
 var (
 	ErrLyricsNotFound        = errors.New("lyrics not found")
 	ErrFailedTranslateLyrics = errors.New("failed translate lyrics")
+	ErrQuotaExceeded         = errors.New("daily translation quota exceeded")
 )
 
+// QuotaExceededError is returned without calling the translator once the
+// client has spent its daily quota. It matches ErrQuotaExceeded with
+// errors.Is.
+type QuotaExceededError struct {
+	Client     string
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Client, ErrQuotaExceeded, e.RetryAfter.Round(time.Second))
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
 const (
 	RequestTimeout = 10 * time.Second
 )
@@ -29,3 +50,27 @@ func FormatLyrics(lyrics string) []string {
 
 	return result
 }
+
+// Ping reports whether url answers without a server error. It doesn't go
+// through the retrying transport, so health probes never change the state
+// of a circuit breaker.
+func Ping(ctx context.Context, url string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return err
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+
+	if resp.StatusCode >= http.StatusInternalServerError {
+		return fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package libretranslate
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/tracing"
+)
+
+const Name = "libretranslate"
+
+type Request struct {
+	Q      []string `json:"q"`
+	Source string   `json:"source"`
+	Target string   `json:"target"`
+	Format string   `json:"format"`
+	APIKey string   `json:"api_key,omitempty"`
+}
+
+type Response struct {
+	TranslatedText []string `json:"translatedText"`
+	Error          string   `json:"error"`
+}
+
+type Client struct {
+	log           *slog.Logger
+	client        *http.Client
+	baseURL       string
+	apiKey        string
+	maxBatchChars int
+	metrics       *metrics.Metrics
+}
+
+func New(
+	log *slog.Logger,
+	baseURL string,
+	apiKey string,
+	maxBatchChars int,
+	transportCfg apiClient.TransportConfig,
+	m *metrics.Metrics,
+) *Client {
+	return &Client{
+		log: log,
+		client: &http.Client{
+			Transport: apiClient.NewTransport(log, Name, transportCfg, tracing.Transport(nil)),
+		},
+		baseURL:       baseURL,
+		apiKey:        apiKey,
+		maxBatchChars: maxBatchChars,
+		metrics:       m,
+	}
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	pingURL, err := url.JoinPath(c.baseURL, "languages")
+	if err != nil {
+		return err
+	}
+
+	return apiClient.Ping(ctx, pingURL)
+}
+
+func (c *Client) TranslateLyrics(
+	ctx context.Context,
+	lyrics []string,
+	targetLang string,
+) ([]string, error) {
+	const op = "service.api.libretranslate.TranslateLyrics"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := c.log.With(slog.String("op", op), slog.String("target_lang", targetLang))
+
+	log.InfoContext(ctx, "translating lyrics")
+
+	translated, err := apiClient.TranslateInBatches(ctx, log, lyrics, c.maxBatchChars,
+		func(ctx context.Context, lines []string) ([]string, error) {
+			return c.translateBatch(ctx, log, lines, targetLang)
+		},
+	)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return translated, nil
+}
+
+// translateBatch sends the lines as an array of texts, for which
+// LibreTranslate returns an array of translations in the same order.
+func (c *Client) translateBatch(
+	ctx context.Context,
+	log *slog.Logger,
+	lines []string,
+	targetLang string,
+) ([]string, error) {
+	req, err := c.buildAPIRequest(ctx, lines, targetLang)
+	if err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	res, err := c.doAPIRequest(req)
+
+	c.metrics.ObserveUpstreamCall(Name, start, err != nil)
+
+	if err != nil {
+		return nil, err
+	}
+
+	log.DebugContext(ctx, "libretranslate response", slog.Any("response", res))
+
+	return res.TranslatedText, nil
+}
+
+func (c *Client) buildAPIRequest(
+	ctx context.Context,
+	lines []string,
+	targetLang string,
+) (*http.Request, error) {
+	apiURL, err := url.JoinPath(c.baseURL, "translate")
+	if err != nil {
+		return nil, err
+	}
+
+	reqBody, err := json.Marshal(Request{
+		Q:      lines,
+		Source: "auto",
+		Target: targetLang,
+		Format: "text",
+		APIKey: c.apiKey,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, apiURL, bytes.NewBuffer(reqBody))
+	if err != nil {
+		return nil, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	return req, nil
+}
+
+func (c *Client) doAPIRequest(req *http.Request) (*Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	var res Response
+	if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+		}
+
+		return nil, err
+	}
+
+	// LibreTranslate answers 400 to texts or languages it can't handle.
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %s", apiClient.ErrFailedTranslateLyrics, res.Error)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s: %s", resp.Status, res.Error)
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,183 @@
+package libretranslate_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/client/libretranslate"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+type fakeServer struct {
+	mu       sync.Mutex
+	requests []libretranslate.Request
+	respond  func(w http.ResponseWriter, req libretranslate.Request)
+}
+
+func (f *fakeServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Path == "/languages" {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+
+	if r.Method != http.MethodPost || r.URL.Path != "/translate" {
+		http.NotFound(w, r)
+		return
+	}
+
+	var req libretranslate.Request
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, req)
+	f.mu.Unlock()
+
+	f.respond(w, req)
+}
+
+// echo translates every text by prefixing it with the target language.
+func echo(w http.ResponseWriter, req libretranslate.Request) {
+	texts := make([]string, len(req.Q))
+	for i, q := range req.Q {
+		texts[i] = req.Target + ":" + q
+	}
+
+	_ = json.NewEncoder(w).Encode(libretranslate.Response{TranslatedText: texts})
+}
+
+func newClient(t *testing.T, respond func(w http.ResponseWriter, req libretranslate.Request), budget int) (*libretranslate.Client, *fakeServer) {
+	t.Helper()
+
+	fake := &fakeServer{respond: respond}
+
+	srv := httptest.NewServer(fake)
+	t.Cleanup(srv.Close)
+
+	client := libretranslate.New(slogdiscard.NewDiscardLogger(), srv.URL, "secret", budget,
+		apiClient.TransportConfig{}, nil)
+
+	return client, fake
+}
+
+func TestTranslateLyrics(t *testing.T) {
+	lyrics := []string{"one", "two", "three", "four"}
+
+	client, fake := newClient(t, echo, 8)
+
+	got, err := client.TranslateLyrics(context.Background(), lyrics, "de")
+	if err != nil {
+		t.Fatalf("TranslateLyrics() error = %v", err)
+	}
+
+	want := []string{"de:one", "de:two", "de:three", "de:four"}
+	if !slices.Equal(got, want) {
+		t.Errorf("TranslateLyrics() = %q, want %q", got, want)
+	}
+
+	// "one"+"two" fit into 8 characters, "three" and "four" don't.
+	wantBatches := [][]string{{"one", "two"}, {"three"}, {"four"}}
+	if len(fake.requests) != len(wantBatches) {
+		t.Fatalf("got %d requests, want %d", len(fake.requests), len(wantBatches))
+	}
+
+	for i, req := range fake.requests {
+		if !slices.Equal(req.Q, wantBatches[i]) {
+			t.Errorf("request %d q = %q, want %q", i, req.Q, wantBatches[i])
+		}
+
+		if req.Target != "de" || req.Source != "auto" || req.Format != "text" {
+			t.Errorf("request %d = %+v, want source auto, target de and format text", i, req)
+		}
+
+		if req.APIKey != "secret" {
+			t.Errorf("request %d api_key = %q, want %q", i, req.APIKey, "secret")
+		}
+	}
+}
+
+func TestTranslateLyricsErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		lyrics   []string
+		respond  func(w http.ResponseWriter, req libretranslate.Request)
+		wantErr  error
+		requests int
+	}{
+		{
+			name:   "fewer lines than sent",
+			lyrics: []string{"one", "two"},
+			respond: func(w http.ResponseWriter, req libretranslate.Request) {
+				_ = json.NewEncoder(w).Encode(libretranslate.Response{TranslatedText: []string{"eins zwei"}})
+			},
+			wantErr:  apiClient.ErrTranslationMisaligned,
+			requests: 1,
+		},
+		{
+			name:   "unsupported language",
+			lyrics: []string{"one"},
+			respond: func(w http.ResponseWriter, req libretranslate.Request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_ = json.NewEncoder(w).Encode(libretranslate.Response{Error: "de is not supported"})
+			},
+			wantErr:  apiClient.ErrFailedTranslateLyrics,
+			requests: 1,
+		},
+		{
+			name:     "line longer than the budget",
+			lyrics:   []string{"one", strings.Repeat("x", 100)},
+			respond:  echo,
+			wantErr:  apiClient.ErrLineTooLong,
+			requests: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, fake := newClient(t, tt.respond, 50)
+
+			_, err := client.TranslateLyrics(context.Background(), tt.lyrics, "de")
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("TranslateLyrics() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if len(fake.requests) != tt.requests {
+				t.Errorf("got %d requests, want %d", len(fake.requests), tt.requests)
+			}
+		})
+	}
+}
+
+func TestTranslateLyricsServerError(t *testing.T) {
+	client, _ := newClient(t, func(w http.ResponseWriter, req libretranslate.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+		_ = json.NewEncoder(w).Encode(libretranslate.Response{Error: "boom"})
+	}, 50)
+
+	_, err := client.TranslateLyrics(context.Background(), []string{"one"}, "de")
+	if err == nil {
+		t.Fatal("TranslateLyrics() error = nil, want an error")
+	}
+
+	if errors.Is(err, apiClient.ErrFailedTranslateLyrics) {
+		t.Errorf("TranslateLyrics() error = %v, a server error must not look like a rejected text", err)
+	}
+}
+
+func TestPing(t *testing.T) {
+	client, _ := newClient(t, echo, 50)
+
+	if err := client.Ping(context.Background()); err != nil {
+		t.Errorf("Ping() error = %v", err)
+	}
+}
@@ -0,0 +1,129 @@
+package lrclib
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"time"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/tracing"
+)
+
+const (
+	Name = "lrclib"
+
+	apiBaseURL = "https://lrclib.net/api/get"
+)
+
+type Client struct {
+	log     *slog.Logger
+	client  *http.Client
+	metrics *metrics.Metrics
+}
+
+func New(log *slog.Logger, transportCfg apiClient.TransportConfig, m *metrics.Metrics) *Client {
+	return &Client{
+		log: log,
+		client: &http.Client{
+			Transport: apiClient.NewTransport(log, Name, transportCfg, tracing.Transport(nil)),
+		},
+		metrics: m,
+	}
+}
+
+func (c *Client) Ping(ctx context.Context) error {
+	return apiClient.Ping(ctx, apiBaseURL)
+}
+
+type LyricsResponse struct {
+	PlainLyrics  string `json:"plainLyrics"`
+	SyncedLyrics string `json:"syncedLyrics"`
+	Instrumental bool   `json:"instrumental"`
+}
+
+func (c *Client) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+	const op = "service.api.lrclib.Lyrics"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := c.log.With(slog.String("op", op),
+		slog.String("artist", artist),
+		slog.String("title", title),
+	)
+
+	log.InfoContext(ctx, "Fetching lyrics")
+
+	ctx, cancel := context.WithTimeout(ctx, apiClient.RequestTimeout)
+	defer cancel()
+
+	query := url.Values{}
+	query.Set("artist_name", artist)
+	query.Set("track_name", title)
+
+	apiURL := apiBaseURL + "?" + query.Encode()
+
+	log.DebugContext(ctx, "Api URL", slog.String("url", apiURL))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	start := time.Now()
+
+	result, err := c.doAPIRequest(req)
+
+	c.metrics.ObserveUpstreamCall(Name, start,
+		err != nil && !errors.Is(err, apiClient.ErrLyricsNotFound))
+
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	formatted := apiClient.FormatLyrics(result.PlainLyrics)
+	if len(formatted) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, apiClient.ErrLyricsNotFound)
+	}
+
+	log.InfoContext(ctx, "lyrics fetched successfully")
+
+	return &models.Lyrics{
+		Source: Name,
+		Lines:  formatted,
+	}, nil
+}
+
+func (c *Client) doAPIRequest(req *http.Request) (*LyricsResponse, error) {
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, apiClient.ErrLyricsNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
+	var result LyricsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	return &result, nil
+}
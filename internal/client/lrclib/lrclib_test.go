@@ -0,0 +1,138 @@
+package lrclib
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+// redirect sends every request to the test server instead of the real API.
+type redirect struct {
+	target *url.URL
+}
+
+func (t redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+
+	return &Client{
+		log:    slogdiscard.NewDiscardLogger(),
+		client: &http.Client{Transport: redirect{target: target}},
+	}
+}
+
+func TestLyrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "plain lyrics are used",
+			status: http.StatusOK,
+			body:   `{"plainLyrics":"first\n\n  second  \n","syncedLyrics":"[00:01.00] first\n[00:02.00] second","instrumental":false}`,
+			want:   []string{"first", "second"},
+		},
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			body:    `{"code":404,"name":"TrackNotFound","message":"Failed to find specified track"}`,
+			wantErr: apiClient.ErrLyricsNotFound,
+		},
+		{
+			name:    "instrumental",
+			status:  http.StatusOK,
+			body:    `{"plainLyrics":null,"syncedLyrics":null,"instrumental":true}`,
+			wantErr: apiClient.ErrLyricsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				if got := r.URL.Query().Get("artist_name"); got != "artist" {
+					t.Errorf("artist_name = %q, want %q", got, "artist")
+				}
+
+				if got := r.URL.Query().Get("track_name"); got != "title" {
+					t.Errorf("track_name = %q, want %q", got, "title")
+				}
+
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			lyrics, err := c.Lyrics(context.Background(), "artist", "title")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Lyrics() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Lyrics() error = %v", err)
+			}
+
+			if !slices.Equal(lyrics.Lines, tt.want) {
+				t.Errorf("Lyrics() lines = %q, want %q", lyrics.Lines, tt.want)
+			}
+
+			if lyrics.Source != Name {
+				t.Errorf("Lyrics() source = %q, want %q", lyrics.Source, Name)
+			}
+		})
+	}
+}
+
+func TestLyricsErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		body   string
+	}{
+		{name: "unexpected status", status: http.StatusBadRequest, body: `{"code":400}`},
+		{name: "malformed body", status: http.StatusOK, body: `{"plainLyrics":`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			_, err := c.Lyrics(context.Background(), "artist", "title")
+			if err == nil {
+				t.Fatal("Lyrics() error = nil, want an error")
+			}
+
+			if errors.Is(err, apiClient.ErrLyricsNotFound) {
+				t.Errorf("Lyrics() error = %v, want an error other than not found", err)
+			}
+		})
+	}
+}
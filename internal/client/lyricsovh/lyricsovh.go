@@ -3,44 +3,62 @@ package lyricsovh
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"time"
 
 	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/tracing"
 )
 
 const (
+	Name = "lyricsovh"
+
 	apiBaseURL = "https://api.lyrics.ovh/v1"
 )
 
 type Client struct {
-	log    *slog.Logger
-	client *http.Client
+	log     *slog.Logger
+	client  *http.Client
+	metrics *metrics.Metrics
 }
 
-func New(log *slog.Logger) *Client {
+func New(log *slog.Logger, transportCfg apiClient.TransportConfig, m *metrics.Metrics) *Client {
 	return &Client{
-		log:    log,
-		client: &http.Client{},
+		log: log,
+		client: &http.Client{
+			Transport: apiClient.NewTransport(log, Name, transportCfg, tracing.Transport(nil)),
+		},
+		metrics: m,
 	}
 }
 
+func (c *Client) Ping(ctx context.Context) error {
+	return apiClient.Ping(ctx, apiBaseURL)
+}
+
 type LyricsResponse struct {
 	Lyrics string `json:"lyrics"`
 	Error  string `json:"error"`
 }
 
-func (c *Client) Lyrics(ctx context.Context, artist, title string) ([]string, error) {
+func (c *Client) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
 	const op = "service.api.lyricsovh.Lyrics"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	log := c.log.With(slog.String("op", op),
 		slog.String("artist", artist),
 		slog.String("title", title),
 	)
 
-	log.Info("Fetching lyrics")
+	log.InfoContext(ctx, "Fetching lyrics")
 
 	ctx, cancel := context.WithTimeout(ctx, apiClient.RequestTimeout)
 	defer cancel()
@@ -50,25 +68,41 @@ func (c *Client) Lyrics(ctx context.Context, artist, title string) ([]string, er
 		return nil, err
 	}
 
-	log.Debug("Api URL", slog.String("url", apiURL))
+	log.DebugContext(ctx, "Api URL", slog.String("url", apiURL))
 
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, apiURL, nil)
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	start := time.Now()
+
 	result, err := c.doAPIRequest(req)
+
+	c.metrics.ObserveUpstreamCall(Name, start,
+		err != nil && !errors.Is(err, apiClient.ErrLyricsNotFound))
+
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	log.Debug("Lyrics response", slog.Any("response", result))
+	log.DebugContext(ctx, "Lyrics response", slog.Any("response", result))
 
 	formatted := apiClient.FormatLyrics(result.Lyrics)
+	if len(formatted) == 0 {
+		return nil, fmt.Errorf("%s: %w", op, apiClient.ErrLyricsNotFound)
+	}
 
-	log.Info("lyrics fetched successfully")
+	log.InfoContext(ctx, "lyrics fetched successfully")
 
-	return formatted, nil
+	return &models.Lyrics{
+		Source: Name,
+		Lines:  formatted,
+	}, nil
 }
 
 func (c *Client) doAPIRequest(req *http.Request) (*LyricsResponse, error) {
@@ -78,6 +112,14 @@ func (c *Client) doAPIRequest(req *http.Request) (*LyricsResponse, error) {
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, apiClient.ErrLyricsNotFound
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s", resp.Status)
+	}
+
 	var result LyricsResponse
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
 		return nil, err
@@ -0,0 +1,140 @@
+package lyricsovh
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+// redirect sends every request to the test server instead of the real API.
+type redirect struct {
+	target *url.URL
+}
+
+func (t redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+func newTestClient(t *testing.T, handler http.HandlerFunc) *Client {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+
+	return &Client{
+		log:    slogdiscard.NewDiscardLogger(),
+		client: &http.Client{Transport: redirect{target: target}},
+	}
+}
+
+func TestLyrics(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		body    string
+		want    []string
+		wantErr error
+	}{
+		{
+			name:   "lines are trimmed",
+			status: http.StatusOK,
+			body:   `{"lyrics":"first\r\n\n  second  \n"}`,
+			want:   []string{"first", "second"},
+		},
+		{
+			name:    "not found",
+			status:  http.StatusNotFound,
+			body:    `{"error":"No lyrics found"}`,
+			wantErr: apiClient.ErrLyricsNotFound,
+		},
+		{
+			name:    "empty lyrics",
+			status:  http.StatusOK,
+			body:    `{"lyrics":""}`,
+			wantErr: apiClient.ErrLyricsNotFound,
+		},
+		{
+			name:    "whitespace-only lyrics",
+			status:  http.StatusOK,
+			body:    `{"lyrics":" \r\n\n\t "}`,
+			wantErr: apiClient.ErrLyricsNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = w.Write([]byte(tt.body))
+			})
+
+			lyrics, err := c.Lyrics(context.Background(), "artist", "title")
+
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Lyrics() error = %v, want %v", err, tt.wantErr)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("Lyrics() error = %v", err)
+			}
+
+			if !slices.Equal(lyrics.Lines, tt.want) {
+				t.Errorf("Lyrics() lines = %q, want %q", lyrics.Lines, tt.want)
+			}
+
+			if lyrics.Source != Name {
+				t.Errorf("Lyrics() source = %q, want %q", lyrics.Source, Name)
+			}
+		})
+	}
+}
+
+func TestLyricsCancelled(t *testing.T) {
+	served := make(chan struct{})
+
+	c := newTestClient(t, func(w http.ResponseWriter, r *http.Request) {
+		close(served)
+
+		<-r.Context().Done()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-served
+		cancel()
+	}()
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := c.Lyrics(ctx, "artist", "title")
+		done <- err
+	}()
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("Lyrics() error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("Lyrics() doesn't return after the context is cancelled")
+	}
+}
@@ -0,0 +1,88 @@
+package quota
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+)
+
+type LyricsTranslator interface {
+	TranslateLyrics(ctx context.Context, lyrics []string, targetLang string) ([]string, error)
+}
+
+type Counter interface {
+	ChargeTranslationQuota(ctx context.Context, client string, day time.Time, chars, limit int64) (bool, error)
+}
+
+// Translator charges the characters sent to the translator against a daily
+// quota of the client from the request context. Days are UTC days.
+type Translator struct {
+	log        *slog.Logger
+	translator LyricsTranslator
+	counter    Counter
+	dailyChars int64
+}
+
+func New(
+	log *slog.Logger,
+	translator LyricsTranslator,
+	counter Counter,
+	dailyChars int64,
+) *Translator {
+	return &Translator{
+		log:        log,
+		translator: translator,
+		counter:    counter,
+		dailyChars: dailyChars,
+	}
+}
+
+// TranslateLyrics charges the quota before translating, a failed
+// translation is not refunded. When the counter fails the text is
+// translated anyway.
+func (t *Translator) TranslateLyrics(
+	ctx context.Context,
+	lyrics []string,
+	targetLang string,
+) ([]string, error) {
+	const op = "client.quota.TranslateLyrics"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	client := clientid.FromContext(ctx)
+
+	log := t.log.With(slog.String("op", op), slog.String("client", client))
+
+	var chars int64
+	for _, line := range lyrics {
+		chars += int64(utf8.RuneCountInString(line))
+	}
+
+	now := time.Now().UTC()
+
+	charged, err := t.counter.ChargeTranslationQuota(ctx, client, now, chars, t.dailyChars)
+	switch {
+	case err != nil:
+		log.ErrorContext(ctx, "failed to charge translation quota", sl.Err(err))
+	case !charged:
+		log.WarnContext(ctx, "daily translation quota exceeded", slog.Int64("chars", chars))
+
+		nextDay := now.Truncate(24 * time.Hour).Add(24 * time.Hour)
+
+		err := &apiClient.QuotaExceededError{Client: client, RetryAfter: nextDay.Sub(now)}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return t.translator.TranslateLyrics(ctx, lyrics, targetLang)
+}
@@ -0,0 +1,96 @@
+package quota_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/client/quota"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/storage/memory"
+)
+
+type translatorFunc func(ctx context.Context, lyrics []string, targetLang string) ([]string, error)
+
+func (f translatorFunc) TranslateLyrics(ctx context.Context, lyrics []string, targetLang string) ([]string, error) {
+	return f(ctx, lyrics, targetLang)
+}
+
+type counterFunc func(ctx context.Context, client string, day time.Time, chars, limit int64) (bool, error)
+
+func (f counterFunc) ChargeTranslationQuota(ctx context.Context, client string, day time.Time, chars, limit int64) (bool, error) {
+	return f(ctx, client, day, chars, limit)
+}
+
+func echo(calls *int) translatorFunc {
+	return func(_ context.Context, lyrics []string, _ string) ([]string, error) {
+		*calls++
+
+		return lyrics, nil
+	}
+}
+
+func TestTranslateLyrics(t *testing.T) {
+	var calls int
+
+	// "Привет" and "мир" are 9 characters, not 15 bytes.
+	translator := quota.New(slogdiscard.NewDiscardLogger(), echo(&calls), memory.NewLimiter(), 20)
+
+	alice := clientid.WithContext(context.Background(), "key:alice")
+	bob := clientid.WithContext(context.Background(), "key:bob")
+
+	for i := range 2 {
+		if _, err := translator.TranslateLyrics(alice, []string{"Привет", "мир"}, "en"); err != nil {
+			t.Fatalf("TranslateLyrics() #%d error = %v", i, err)
+		}
+	}
+
+	_, err := translator.TranslateLyrics(alice, []string{"Привет", "мир"}, "en")
+
+	var quotaErr *apiClient.QuotaExceededError
+	if !errors.As(err, &quotaErr) {
+		t.Fatalf("TranslateLyrics() error = %v, want %v", err, apiClient.ErrQuotaExceeded)
+	}
+
+	if quotaErr.Client != "key:alice" || quotaErr.RetryAfter <= 0 || quotaErr.RetryAfter > 24*time.Hour {
+		t.Errorf("TranslateLyrics() error = %+v, want a retry before the next UTC day", quotaErr)
+	}
+
+	if calls != 2 {
+		t.Errorf("translator called %d times, want 2", calls)
+	}
+
+	// Every client has a quota of its own.
+	if _, err := translator.TranslateLyrics(bob, []string{"Привет", "мир"}, "en"); err != nil {
+		t.Errorf("TranslateLyrics() of another client error = %v", err)
+	}
+}
+
+func TestTranslateLyricsCounterFails(t *testing.T) {
+	var (
+		calls  int
+		client string
+	)
+
+	translator := quota.New(slogdiscard.NewDiscardLogger(), echo(&calls),
+		counterFunc(func(_ context.Context, c string, _ time.Time, _, _ int64) (bool, error) {
+			client = c
+
+			return false, errors.New("redis: connection refused")
+		}), 20)
+
+	if _, err := translator.TranslateLyrics(context.Background(), []string{"line"}, "en"); err != nil {
+		t.Fatalf("TranslateLyrics() error = %v", err)
+	}
+
+	if calls != 1 {
+		t.Errorf("translator called %d times, want 1", calls)
+	}
+
+	if client != clientid.Anonymous {
+		t.Errorf("charged client = %q, want %q", client, clientid.Anonymous)
+	}
+}
@@ -0,0 +1,263 @@
+package client
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+// CircuitOpenError is returned without calling the upstream while its
+// circuit breaker is open. It matches ErrCircuitOpen with errors.Is.
+type CircuitOpenError struct {
+	Upstream   string
+	RetryAfter time.Duration
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("%s: %s, retry after %s", e.Upstream, ErrCircuitOpen, e.RetryAfter.Round(time.Second))
+}
+
+func (e *CircuitOpenError) Unwrap() error {
+	return ErrCircuitOpen
+}
+
+type TransportConfig struct {
+	// MaxRetries is the number of attempts after the first one.
+	MaxRetries int
+	BaseDelay  time.Duration
+	MaxDelay   time.Duration
+	// BreakerThreshold is the number of failed calls in a row that opens
+	// the circuit, zero disables the breaker.
+	BreakerThreshold int
+	BreakerCooldown  time.Duration
+}
+
+// Transport retries requests failed with a network error, 5xx or 429 and
+// guards the upstream with a circuit breaker. A call counts as failed for
+// the breaker only once its retries are exhausted. A Retry-After longer
+// than MaxDelay is not waited for, the response is returned as it is.
+type Transport struct {
+	log      *slog.Logger
+	upstream string
+	cfg      TransportConfig
+	base     http.RoundTripper
+	breaker  *breaker
+}
+
+func NewTransport(
+	log *slog.Logger,
+	upstream string,
+	cfg TransportConfig,
+	base http.RoundTripper,
+) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	return &Transport{
+		log:      log.With(slog.String("upstream", upstream)),
+		upstream: upstream,
+		cfg:      cfg,
+		base:     base,
+		breaker:  &breaker{threshold: cfg.BreakerThreshold, cooldown: cfg.BreakerCooldown},
+	}
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if wait, ok := t.breaker.allow(); !ok {
+		return nil, &CircuitOpenError{Upstream: t.upstream, RetryAfter: wait}
+	}
+
+	resp, err := t.roundTrip(req)
+
+	failed := err != nil || retryableStatus(resp.StatusCode)
+	if req.Context().Err() != nil {
+		// The caller gave up, that says nothing about the upstream.
+		t.breaker.release()
+	} else if t.breaker.record(failed) {
+		t.log.WarnContext(req.Context(), "circuit breaker opened", slog.Duration("cooldown", t.cfg.BreakerCooldown))
+	}
+
+	return resp, err
+}
+
+func (t *Transport) roundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		if attempt > 0 && req.Body != nil {
+			if req.GetBody == nil {
+				return nil, errors.New("request body can't be replayed for retry")
+			}
+
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+
+			req = req.Clone(req.Context())
+			req.Body = body
+		}
+
+		resp, err := t.base.RoundTrip(req)
+
+		if req.Context().Err() != nil || attempt >= t.cfg.MaxRetries {
+			return resp, err
+		}
+
+		if err == nil && !retryableStatus(resp.StatusCode) {
+			return resp, nil
+		}
+
+		delay := t.backoff(attempt)
+
+		if err == nil {
+			if after, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+				if after > t.cfg.MaxDelay {
+					// Calling sooner than the upstream asked only burns the
+					// retries, the caller gets its answer instead.
+					t.log.DebugContext(req.Context(), "upstream asked to retry later than the max delay",
+						slog.Duration("retry_after", after),
+					)
+
+					return resp, nil
+				}
+
+				delay = after
+			}
+
+			io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+			resp.Body.Close()
+		}
+
+		t.log.DebugContext(req.Context(), "retrying upstream request",
+			slog.Int("attempt", attempt+1),
+			slog.Duration("delay", delay),
+		)
+
+		timer := time.NewTimer(delay)
+
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns a random delay up to BaseDelay*2^attempt, capped at
+// MaxDelay ("full jitter").
+func (t *Transport) backoff(attempt int) time.Duration {
+	ceiling := t.cfg.BaseDelay << attempt
+	if ceiling <= 0 || ceiling > t.cfg.MaxDelay {
+		ceiling = t.cfg.MaxDelay
+	}
+
+	if ceiling <= 0 {
+		return 0
+	}
+
+	return rand.N(ceiling)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusTooManyRequests || code >= http.StatusInternalServerError
+}
+
+// retryAfter parses the Retry-After header given either in seconds or as
+// an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0), true
+	}
+
+	return 0, false
+}
+
+// breaker opens after threshold failed calls in a row. Once the cooldown
+// has passed a single probe call is let through: its success closes the
+// circuit, its failure opens it for another cooldown.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+func (b *breaker) allow() (time.Duration, bool) {
+	if b.threshold <= 0 {
+		return 0, true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.failures < b.threshold {
+		return 0, true
+	}
+
+	if wait := time.Until(b.openUntil); wait > 0 {
+		return wait, false
+	}
+
+	if b.probing {
+		return b.cooldown, false
+	}
+
+	b.probing = true
+
+	return 0, true
+}
+
+// record reports whether the call has opened the circuit.
+func (b *breaker) record(failed bool) bool {
+	if b.threshold <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+
+	if !failed {
+		b.failures = 0
+
+		return false
+	}
+
+	b.failures++
+
+	if b.failures < b.threshold {
+		return false
+	}
+
+	b.openUntil = time.Now().Add(b.cooldown)
+
+	return true
+}
+
+func (b *breaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.probing = false
+}
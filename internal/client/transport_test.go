@@ -0,0 +1,418 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+// failFirst answers the first n requests with fail and the rest with 200.
+func failFirst(n int32, fail func(w http.ResponseWriter)) (http.HandlerFunc, *atomic.Int32) {
+	var calls atomic.Int32
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) <= n {
+			fail(w)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}, &calls
+}
+
+func status(code int) func(w http.ResponseWriter) {
+	return func(w http.ResponseWriter) {
+		w.WriteHeader(code)
+	}
+}
+
+// hangUp drops the connection without a response.
+func hangUp(w http.ResponseWriter) {
+	conn, _, err := w.(http.Hijacker).Hijack()
+	if err == nil {
+		conn.Close()
+	}
+}
+
+func newTestClient(t *testing.T, handler http.Handler, cfg TransportConfig) (*http.Client, string) {
+	t.Helper()
+
+	srv := httptest.NewServer(handler)
+	t.Cleanup(srv.Close)
+
+	transport := NewTransport(slogdiscard.NewDiscardLogger(), "test", cfg, srv.Client().Transport)
+
+	return &http.Client{Transport: transport}, srv.URL
+}
+
+var fastRetries = TransportConfig{
+	MaxRetries: 2,
+	BaseDelay:  time.Millisecond,
+	MaxDelay:   5 * time.Millisecond,
+}
+
+func TestTransportRetries(t *testing.T) {
+	tests := []struct {
+		name       string
+		fail       func(w http.ResponseWriter)
+		wantStatus int
+		wantCalls  int32
+	}{
+		{name: "500", fail: status(http.StatusInternalServerError), wantStatus: http.StatusOK, wantCalls: 2},
+		{name: "503", fail: status(http.StatusServiceUnavailable), wantStatus: http.StatusOK, wantCalls: 2},
+		{name: "429", fail: status(http.StatusTooManyRequests), wantStatus: http.StatusOK, wantCalls: 2},
+		{name: "network error", fail: hangUp, wantStatus: http.StatusOK},
+		{name: "400 is not retried", fail: status(http.StatusBadRequest), wantStatus: http.StatusBadRequest, wantCalls: 1},
+		{name: "404 is not retried", fail: status(http.StatusNotFound), wantStatus: http.StatusNotFound, wantCalls: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler, calls := failFirst(1, tt.fail)
+			client, url := newTestClient(t, handler, fastRetries)
+
+			resp, err := client.Get(url)
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			resp.Body.Close()
+
+			if resp.StatusCode != tt.wantStatus {
+				t.Errorf("status = %d, want %d", resp.StatusCode, tt.wantStatus)
+			}
+
+			// The standard transport may retry a dropped connection on its
+			// own, so only the lower bound is checked for network errors.
+			switch {
+			case tt.wantCalls == 0 && calls.Load() < 2:
+				t.Errorf("upstream called %d times, want a retry", calls.Load())
+			case tt.wantCalls != 0 && calls.Load() != tt.wantCalls:
+				t.Errorf("upstream called %d times, want %d", calls.Load(), tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestTransportMaxRetries(t *testing.T) {
+	handler, calls := failFirst(100, status(http.StatusBadGateway))
+	client, url := newTestClient(t, handler, fastRetries)
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusBadGateway {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusBadGateway)
+	}
+
+	if calls.Load() != 3 {
+		t.Errorf("upstream called %d times, want 3", calls.Load())
+	}
+}
+
+func TestTransportHonorsRetryAfter(t *testing.T) {
+	handler, calls := failFirst(1, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client, url := newTestClient(t, handler, TransportConfig{
+		MaxRetries: 1,
+		BaseDelay:  time.Millisecond,
+		MaxDelay:   5 * time.Second,
+	})
+
+	start := time.Now()
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if elapsed := time.Since(start); elapsed < 900*time.Millisecond {
+		t.Errorf("retried after %s, want about a second", elapsed)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("upstream called %d times, want 2", calls.Load())
+	}
+}
+
+func TestTransportDoesNotShortenRetryAfter(t *testing.T) {
+	handler, calls := failFirst(1, func(w http.ResponseWriter) {
+		w.Header().Set("Retry-After", "60")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+
+	client, url := newTestClient(t, handler, fastRetries)
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusTooManyRequests {
+		t.Errorf("status = %d, want %d", resp.StatusCode, http.StatusTooManyRequests)
+	}
+
+	if got := resp.Header.Get("Retry-After"); got != "60" {
+		t.Errorf("Retry-After = %q, want %q", got, "60")
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("upstream called %d times, want 1", calls.Load())
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := []struct {
+		name   string
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{name: "seconds", value: "3", want: 3 * time.Second, wantOK: true},
+		{name: "zero", value: "0", want: 0, wantOK: true},
+		{name: "past date", value: time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat), want: 0, wantOK: true},
+		{name: "missing", value: ""},
+		{name: "negative", value: "-1"},
+		{name: "garbage", value: "soon"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := retryAfter(tt.value)
+			if ok != tt.wantOK || got != tt.want {
+				t.Errorf("retryAfter(%q) = %s, %v, want %s, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+
+	// A date has a resolution of a second.
+	got, ok := retryAfter(time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat))
+	if !ok || got < 8*time.Second || got > 10*time.Second {
+		t.Errorf("retryAfter(date in 10s) = %s, %v", got, ok)
+	}
+}
+
+func TestTransportCircuitBreaker(t *testing.T) {
+	var healthy atomic.Bool
+
+	var calls atomic.Int32
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cooldown := 50 * time.Millisecond
+
+	client, url := newTestClient(t, handler, TransportConfig{
+		BreakerThreshold: 2,
+		BreakerCooldown:  cooldown,
+	})
+
+	get := func() error {
+		resp, err := client.Get(url)
+		if err != nil {
+			return err
+		}
+
+		return resp.Body.Close()
+	}
+
+	for range 2 {
+		if err := get(); err != nil {
+			t.Fatalf("Get() error = %v", err)
+		}
+	}
+
+	err := get()
+
+	var openErr *CircuitOpenError
+	if !errors.As(err, &openErr) || !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Get() error = %v, want a CircuitOpenError", err)
+	}
+
+	if openErr.RetryAfter <= 0 || openErr.RetryAfter > cooldown {
+		t.Errorf("RetryAfter = %s, want up to %s", openErr.RetryAfter, cooldown)
+	}
+
+	if calls.Load() != 2 {
+		t.Errorf("upstream called %d times while open, want 2", calls.Load())
+	}
+
+	// A failed probe opens the circuit for another cooldown.
+	time.Sleep(cooldown)
+
+	if err := get(); err != nil {
+		t.Fatalf("probe error = %v", err)
+	}
+
+	if err := get(); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Get() after a failed probe error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	// A successful probe closes it.
+	time.Sleep(cooldown)
+	healthy.Store(true)
+
+	for i := range 3 {
+		if err := get(); err != nil {
+			t.Fatalf("Get() #%d after a successful probe error = %v", i, err)
+		}
+	}
+
+	if calls.Load() != 6 {
+		t.Errorf("upstream called %d times, want 6", calls.Load())
+	}
+}
+
+func TestTransportSingleProbe(t *testing.T) {
+	var healthy atomic.Bool
+
+	probing := make(chan struct{})
+	release := make(chan struct{})
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		close(probing)
+		<-release
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	cooldown := 20 * time.Millisecond
+
+	client, url := newTestClient(t, handler, TransportConfig{
+		BreakerThreshold: 1,
+		BreakerCooldown:  cooldown,
+	})
+
+	resp, err := client.Get(url)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	resp.Body.Close()
+
+	time.Sleep(cooldown)
+	healthy.Store(true)
+
+	probeDone := make(chan error, 1)
+	go func() {
+		resp, err := client.Get(url)
+		if err == nil {
+			resp.Body.Close()
+		}
+
+		probeDone <- err
+	}()
+
+	<-probing
+
+	if _, err := client.Get(url); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Get() during the probe error = %v, want %v", err, ErrCircuitOpen)
+	}
+
+	close(release)
+
+	if err := <-probeDone; err != nil {
+		t.Errorf("probe error = %v", err)
+	}
+}
+
+func TestTransportCancelledCallIsNotAFailure(t *testing.T) {
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})
+
+	client, url := newTestClient(t, handler, TransportConfig{
+		BreakerThreshold: 1,
+		BreakerCooldown:  time.Minute,
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if _, err := client.Do(req); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Do() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	ctx, cancel = context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	req, _ = http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if _, err := client.Do(req); errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("Do() error = %v, the cancelled call opened the circuit", err)
+	}
+}
+
+func TestTransportReplaysBody(t *testing.T) {
+	var bodies []string
+
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if len(bodies) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	})
+
+	client, url := newTestClient(t, handler, fastRetries)
+
+	resp, err := client.Post(url, "text/plain", bytes.NewReader([]byte("payload")))
+	if err != nil {
+		t.Fatalf("Post() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if len(bodies) != 2 || bodies[0] != "payload" || bodies[1] != "payload" {
+		t.Errorf("upstream got bodies %q, want the payload twice", bodies)
+	}
+}
+
+func TestTransportBodyWithoutGetBody(t *testing.T) {
+	handler, calls := failFirst(1, status(http.StatusServiceUnavailable))
+	client, url := newTestClient(t, handler, fastRetries)
+
+	// A reader of unknown type leaves GetBody unset.
+	body := io.NopCloser(strings.NewReader("payload"))
+
+	req, _ := http.NewRequest(http.MethodPost, url, body)
+
+	_, err := client.Do(req)
+	if err == nil || !strings.Contains(err.Error(), "can't be replayed") {
+		t.Errorf("Do() error = %v, want a replay error", err)
+	}
+
+	if calls.Load() != 1 {
+		t.Errorf("upstream called %d times, want 1", calls.Load())
+	}
+}
@@ -8,9 +8,11 @@ import (
 	"io"
 	"log/slog"
 	"net/http"
-	"strings"
+	"time"
 
 	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/tracing"
 )
 
 type Response struct {
@@ -20,61 +22,111 @@ type Response struct {
 }
 
 type Client struct {
-	log    *slog.Logger
-	client *http.Client
-	apiKey string
+	log           *slog.Logger
+	client        *http.Client
+	apiKey        string
+	maxBatchChars int
+	metrics       *metrics.Metrics
 }
 
 const (
+	Name = "yandex"
+
 	yandexTranslateURL = "https://translate.api.cloud.yandex.net/translate/v2/translate"
-	targetLanguage     = "ru"
 )
 
-func New(log *slog.Logger, apiKey string) *Client {
+func New(
+	log *slog.Logger,
+	apiKey string,
+	maxBatchChars int,
+	transportCfg apiClient.TransportConfig,
+	m *metrics.Metrics,
+) *Client {
 	return &Client{
-		log:    log,
-		client: &http.Client{},
-		apiKey: apiKey,
+		log: log,
+		client: &http.Client{
+			Transport: apiClient.NewTransport(log, Name, transportCfg, tracing.Transport(nil)),
+		},
+		apiKey:        apiKey,
+		maxBatchChars: maxBatchChars,
+		metrics:       m,
 	}
 }
 
-func (c *Client) TranslateLyrics(ctx context.Context, lyrics []string) ([]string, error) {
+func (c *Client) Ping(ctx context.Context) error {
+	return apiClient.Ping(ctx, yandexTranslateURL)
+}
+
+func (c *Client) TranslateLyrics(
+	ctx context.Context,
+	lyrics []string,
+	targetLang string,
+) ([]string, error) {
 	const op = "service.api.yandex.TranslateLyrics"
 
-	log := c.log.With(slog.String("op", op))
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	log.Info("translating lyrics")
+	log := c.log.With(slog.String("op", op), slog.String("target_lang", targetLang))
 
-	ctx, cancel := context.WithTimeout(ctx, apiClient.RequestTimeout)
-	defer cancel()
+	log.InfoContext(ctx, "translating lyrics")
 
-	req, err := c.buildAPIRequest(ctx, lyrics)
+	translated, err := apiClient.TranslateInBatches(ctx, log, lyrics, c.maxBatchChars,
+		func(ctx context.Context, lines []string) ([]string, error) {
+			return c.translateBatch(ctx, log, lines, targetLang)
+		},
+	)
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	res, err := c.doAPIRequest(log, req)
+	return translated, nil
+}
+
+// translateBatch sends every line as a separate text, so the translator
+// can't merge or split lines and the n-th translation belongs to the n-th
+// line.
+func (c *Client) translateBatch(
+	ctx context.Context,
+	log *slog.Logger,
+	lines []string,
+	targetLang string,
+) ([]string, error) {
+	req, err := c.buildAPIRequest(ctx, lines, targetLang)
 	if err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+		return nil, err
 	}
 
-	log.Debug("yandex translator response", slog.Any("response", res))
+	start := time.Now()
 
-	if len(res.Translations) == 0 {
-		return nil, apiClient.ErrFailedTranslateLyrics
+	res, err := c.doAPIRequest(log, req)
+
+	c.metrics.ObserveUpstreamCall(Name, start, err != nil)
+
+	if err != nil {
+		return nil, err
 	}
 
-	formatted := apiClient.FormatLyrics(res.Translations[0].Text)
+	log.DebugContext(ctx, "yandex translator response", slog.Any("response", res))
 
-	log.Info("lyrics translated successfully")
+	texts := make([]string, 0, len(res.Translations))
+	for _, translation := range res.Translations {
+		texts = append(texts, translation.Text)
+	}
 
-	return formatted, nil
+	return texts, nil
 }
 
-func (c *Client) buildAPIRequest(ctx context.Context, lyrics []string) (*http.Request, error) {
+func (c *Client) buildAPIRequest(
+	ctx context.Context,
+	lyrics []string,
+	targetLang string,
+) (*http.Request, error) {
 	requestData := map[string]interface{}{
-		"texts":              []string{strings.Join(lyrics, "\n")},
-		"targetLanguageCode": targetLanguage,
+		"texts":              lyrics,
+		"targetLanguageCode": targetLang,
 	}
 
 	reqBody, err := json.Marshal(requestData)
@@ -103,13 +155,21 @@ func (c *Client) doAPIRequest(log *slog.Logger, req *http.Request) (*Response, e
 	}
 	defer resp.Body.Close()
 
-	log.Debug("response status", slog.String("status", resp.Status))
+	log.DebugContext(req.Context(), "response status", slog.String("status", resp.Status))
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
 		return nil, err
 	}
 
+	if resp.StatusCode == http.StatusBadRequest {
+		return nil, fmt.Errorf("%w: %s", apiClient.ErrFailedTranslateLyrics, bytes.TrimSpace(body))
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status: %s: %s", resp.Status, bytes.TrimSpace(body))
+	}
+
 	var res Response
 	if err := json.Unmarshal(body, &res); err != nil {
 		return nil, err
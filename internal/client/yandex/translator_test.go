@@ -0,0 +1,138 @@
+package yandex
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+
+	apiClient "lyrics-library/internal/client"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+// redirect sends every request to the test server instead of the real API.
+type redirect struct {
+	target *url.URL
+}
+
+func (t redirect) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	req.URL.Scheme = t.target.Scheme
+	req.URL.Host = t.target.Host
+
+	return http.DefaultTransport.RoundTrip(req)
+}
+
+type request struct {
+	Texts              []string `json:"texts"`
+	TargetLanguageCode string   `json:"targetLanguageCode"`
+}
+
+func newTestClient(t *testing.T, budget int, handler func(w http.ResponseWriter, req request)) (*Client, *[]request) {
+	t.Helper()
+
+	var requests []request
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Authorization"); got != "Api-Key secret" {
+			t.Errorf("Authorization = %q, want %q", got, "Api-Key secret")
+		}
+
+		var req request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		requests = append(requests, req)
+
+		handler(w, req)
+	}))
+	t.Cleanup(srv.Close)
+
+	target, _ := url.Parse(srv.URL)
+
+	return &Client{
+		log:           slogdiscard.NewDiscardLogger(),
+		client:        &http.Client{Transport: redirect{target: target}},
+		apiKey:        "secret",
+		maxBatchChars: budget,
+	}, &requests
+}
+
+// echo translates every text by prefixing it with the target language.
+func echo(w http.ResponseWriter, req request) {
+	var res Response
+	for _, text := range req.Texts {
+		res.Translations = append(res.Translations, struct {
+			Text string `json:"text"`
+		}{Text: req.TargetLanguageCode + ":" + text})
+	}
+
+	_ = json.NewEncoder(w).Encode(res)
+}
+
+func TestTranslateLyrics(t *testing.T) {
+	c, requests := newTestClient(t, 8, echo)
+
+	got, err := c.TranslateLyrics(context.Background(), []string{"one", "two", "three", "four"}, "de")
+	if err != nil {
+		t.Fatalf("TranslateLyrics() error = %v", err)
+	}
+
+	want := []string{"de:one", "de:two", "de:three", "de:four"}
+	if !slices.Equal(got, want) {
+		t.Errorf("TranslateLyrics() = %q, want %q", got, want)
+	}
+
+	// "one"+"two" fit into 8 characters, "three" and "four" don't.
+	wantBatches := [][]string{{"one", "two"}, {"three"}, {"four"}}
+	if len(*requests) != len(wantBatches) {
+		t.Fatalf("got %d requests, want %d", len(*requests), len(wantBatches))
+	}
+
+	for i, req := range *requests {
+		if !slices.Equal(req.Texts, wantBatches[i]) || req.TargetLanguageCode != "de" {
+			t.Errorf("request %d = %+v, want texts %q in de", i, req, wantBatches[i])
+		}
+	}
+}
+
+func TestTranslateLyricsErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		respond func(w http.ResponseWriter, req request)
+		wantErr error
+	}{
+		{
+			name: "fewer lines than sent",
+			respond: func(w http.ResponseWriter, req request) {
+				_, _ = w.Write([]byte(`{"translations":[{"text":"eins zwei"}]}`))
+			},
+			wantErr: apiClient.ErrTranslationMisaligned,
+		},
+		{
+			name: "rejected text",
+			respond: func(w http.ResponseWriter, req request) {
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"message":"unsupported target language"}`))
+			},
+			wantErr: apiClient.ErrFailedTranslateLyrics,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := newTestClient(t, 50, tt.respond)
+
+			_, err := c.TranslateLyrics(context.Background(), []string{"one", "two"}, "de")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("TranslateLyrics() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
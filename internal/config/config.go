@@ -2,6 +2,7 @@ package config
 
 import (
 	"flag"
+	"fmt"
 	"os"
 	"time"
 
@@ -9,40 +10,220 @@ import (
 )
 
 type Config struct {
-	Env                 string              `env:"APP_ENV" env-default:"local"`
-	HTTPServer          HTTPServerConfig    `env-prefix:"SERVER_"`
-	DB                  DBConfig            `env-prefix:"DB_"`
-	Redis               RedisConfig         `env-prefix:"REDIS_"`
-	YandexTranslatorAPI TranslatorAPIConfig `env-prefix:"TRANSLATOR_API_"`
+	Env                 string               `env:"APP_ENV" env-default:"local"`
+	HTTPServer          HTTPServerConfig     `env-prefix:"SERVER_"`
+	Storage             StorageConfig        `env-prefix:"STORAGE_"`
+	Cache               CacheConfig          `env-prefix:"CACHE_"`
+	DB                  DBConfig             `env-prefix:"DB_"`
+	Redis               RedisConfig          `env-prefix:"REDIS_"`
+	YandexTranslatorAPI TranslatorAPIConfig  `env-prefix:"TRANSLATOR_API_"`
+	LibreTranslate      LibreTranslateConfig `env-prefix:"LIBRETRANSLATE_"`
+	Lyrics              LyricsConfig         `env-prefix:"LYRICS_"`
+	Translation         TranslationConfig    `env-prefix:"TRANSLATION_"`
+	Jobs                JobsConfig           `env-prefix:"JOBS_"`
+	Tracing             TracingConfig        `env-prefix:"TRACING_"`
+	Upstream            UpstreamConfig       `env-prefix:"UPSTREAM_"`
+	Auth                AuthConfig           `env-prefix:"AUTH_"`
+	RateLimit           RateLimitConfig      `env-prefix:"RATE_LIMIT_"`
+	Health              HealthConfig         `env-prefix:"HEALTH_"`
 }
 
 type HTTPServerConfig struct {
 	Address     string        `env:"ADDRESS" env-required:"true"`
 	Timeout     time.Duration `env:"TIMEOUT" env-default:"4s"`
 	IdleTimeout time.Duration `env:"IDLE_TIMEOUT" env-default:"60s"`
+	// TrustedProxies lists the addresses or CIDRs of reverse proxies whose
+	// X-Forwarded-For header is trusted.
+	TrustedProxies []string `env:"TRUSTED_PROXIES" env-separator:","`
 }
 
+const (
+	// StorageBackendPostgres keeps data in Postgres and caches it in Redis.
+	StorageBackendPostgres = "postgres"
+	// StorageBackendMemory keeps data and cache in process memory.
+	StorageBackendMemory = "memory"
+)
+
+type StorageConfig struct {
+	Backend string `env:"BACKEND" env-default:"postgres"`
+}
+
+type CacheConfig struct {
+	TrackTTL        time.Duration `env:"TRACK_TTL" env-default:"24h"`
+	ArtistTracksTTL time.Duration `env:"ARTIST_TRACKS_TTL" env-default:"10m"`
+	// Size limits the number of entries of the in-memory cache.
+	Size int `env:"SIZE" env-default:"10000"`
+	// Redis is bypassed after BreakerThreshold failed calls in a row and
+	// pinged every BreakerCooldown until it answers again.
+	BreakerThreshold int           `env:"BREAKER_THRESHOLD" env-default:"5"`
+	BreakerCooldown  time.Duration `env:"BREAKER_COOLDOWN" env-default:"10s"`
+}
+
+// DBConfig and RedisConfig credentials are required only by the postgres
+// storage backend.
 type DBConfig struct {
 	Host     string `env:"HOST" env-default:"localhost"`
 	Port     string `env:"PORT" env-default:"5432"`
-	User     string `env:"USER" env-required:"true"`
-	Password string `env:"PASSWORD" env-required:"true"`
-	Name     string `env:"NAME" env-required:"true"`
+	User     string `env:"USER"`
+	Password string `env:"PASSWORD"`
+	Name     string `env:"NAME"`
+}
+
+// URL returns the connection URL of the database.
+func (db DBConfig) URL() string {
+	return fmt.Sprintf("postgres://%s:%s@%s:%s/%s?sslmode=disable",
+		db.User, db.Password, db.Host, db.Port, db.Name)
+}
+
+func (db DBConfig) validate() error {
+	required := []struct{ name, value string }{
+		{"DB_USER", db.User},
+		{"DB_PASSWORD", db.Password},
+		{"DB_NAME", db.Name},
+	}
+
+	for _, field := range required {
+		if field.value == "" {
+			return fmt.Errorf("%s is required", field.name)
+		}
+	}
+
+	return nil
 }
 
 type RedisConfig struct {
 	Host     string `env:"HOST" env-default:"localhost"`
 	Port     string `env:"PORT" env-default:"6379"`
-	Password string `env:"PASSWORD" env-required:"true"`
+	Password string `env:"PASSWORD"`
 }
 
+// TranslatorAPIConfig holds the Yandex Cloud API key, required only by the
+// yandex translation backend.
 type TranslatorAPIConfig struct {
-	Key string `env:"KEY" env-required:"true"`
+	Key string `env:"KEY"`
+}
+
+type LibreTranslateConfig struct {
+	URL    string `env:"URL" env-default:"http://localhost:5000"`
+	APIKey string `env:"API_KEY"`
+}
+
+type LyricsConfig struct {
+	Providers []string `env:"PROVIDERS" env-separator:"," env-default:"lyricsovh,lrclib"`
+}
+
+const (
+	TranslationBackendYandex         = "yandex"
+	TranslationBackendLibreTranslate = "libretranslate"
+)
+
+type TranslationConfig struct {
+	Backend            string   `env:"BACKEND" env-default:"yandex"`
+	DefaultTargetLangs []string `env:"DEFAULT_TARGET_LANGS" env-separator:"," env-default:"ru"`
+	// MaxBatchChars limits the characters sent in one translation request.
+	MaxBatchChars int `env:"MAX_BATCH_CHARS" env-default:"10000"`
+	// DailyQuotaChars limits the characters a client may send to the
+	// translator per UTC day, zero disables the quota.
+	DailyQuotaChars int64 `env:"DAILY_QUOTA_CHARS" env-default:"100000"`
+}
+
+type JobsConfig struct {
+	Workers      int           `env:"WORKERS" env-default:"4"`
+	PollInterval time.Duration `env:"POLL_INTERVAL" env-default:"1s"`
+	Timeout      time.Duration `env:"TIMEOUT" env-default:"2m"`
+	// MaxAttempts fails a job claimed more often, counting runs interrupted
+	// by a shutdown or a crashed worker.
+	MaxAttempts int `env:"MAX_ATTEMPTS" env-default:"3"`
+	// RetryDelay is waited before a job that failed with a transient error,
+	// e.g. an unavailable upstream, is run again.
+	RetryDelay time.Duration `env:"RETRY_DELAY" env-default:"30s"`
+	// Retention is how long succeeded and failed jobs are kept, zero keeps
+	// them forever.
+	Retention time.Duration `env:"RETENTION" env-default:"168h"`
+}
+
+// TracingConfig selects where spans are exported: none, stdout or otlp.
+// The OTLP exporter also honours the standard OTEL_EXPORTER_OTLP_* variables.
+type TracingConfig struct {
+	Exporter     string  `env:"EXPORTER" env-default:"none"`
+	ServiceName  string  `env:"SERVICE_NAME" env-default:"lyrics-library"`
+	OTLPEndpoint string  `env:"OTLP_ENDPOINT"`
+	OTLPInsecure bool    `env:"OTLP_INSECURE" env-default:"false"`
+	SampleRatio  float64 `env:"SAMPLE_RATIO" env-default:"1"`
+}
+
+// UpstreamConfig tunes retries and the circuit breaker of every upstream
+// API client.
+type UpstreamConfig struct {
+	MaxRetries       int           `env:"MAX_RETRIES" env-default:"2"`
+	RetryBaseDelay   time.Duration `env:"RETRY_BASE_DELAY" env-default:"200ms"`
+	RetryMaxDelay    time.Duration `env:"RETRY_MAX_DELAY" env-default:"2s"`
+	BreakerThreshold int           `env:"BREAKER_THRESHOLD" env-default:"5"`
+	BreakerCooldown  time.Duration `env:"BREAKER_COOLDOWN" env-default:"30s"`
+}
+
+// AuthConfig switches API key authentication. Keys are managed with
+// cmd/apikeys, so authentication needs the postgres storage backend.
+type AuthConfig struct {
+	Enabled bool `env:"ENABLED" env-default:"true"`
+}
+
+// RateLimitConfig sets the token bucket of every client: RPS requests per
+// second with bursts of up to Burst requests. With authentication enabled
+// every IP address also gets an IPRPS/IPBurst bucket checked before the key
+// is.
+type RateLimitConfig struct {
+	Enabled bool    `env:"ENABLED" env-default:"true"`
+	RPS     float64 `env:"RPS" env-default:"5"`
+	Burst   int     `env:"BURST" env-default:"20"`
+	IPRPS   float64 `env:"IP_RPS" env-default:"20"`
+	IPBurst int     `env:"IP_BURST" env-default:"40"`
+}
+
+// HealthConfig sets how often /readyz dependencies are probed. Upstream
+// APIs are probed only when ProbeUpstreams is set and never make the
+// service unready.
+type HealthConfig struct {
+	Interval       time.Duration `env:"INTERVAL" env-default:"10s"`
+	Timeout        time.Duration `env:"TIMEOUT" env-default:"2s"`
+	ProbeUpstreams bool          `env:"PROBE_UPSTREAMS" env-default:"false"`
 }
 
 // MustLoad Load config file and panic if errors occurs
 func MustLoad() *Config {
-	path := fetchConfigPath()
+	var cfg Config
+
+	mustRead(fetchConfigPath(), &cfg)
+
+	if err := cfg.validate(); err != nil {
+		panic("invalid config: " + err.Error())
+	}
+
+	return &cfg
+}
+
+// MustLoadDB loads only the database settings of the config file at path,
+// or at CONFIG_PATH when path is empty, and panics if errors occur. It is
+// meant for the command line tools, which need none of the server settings.
+func MustLoadDB(path string) *DBConfig {
+	if path == "" {
+		path = os.Getenv("CONFIG_PATH")
+	}
+
+	var cfg struct {
+		DB DBConfig `env-prefix:"DB_"`
+	}
+
+	mustRead(path, &cfg)
+
+	if err := cfg.DB.validate(); err != nil {
+		panic("invalid config: " + err.Error())
+	}
+
+	return &cfg.DB
+}
+
+func mustRead(path string, cfg any) {
 	if path == "" {
 		panic("config file path is empty")
 	}
@@ -51,13 +232,9 @@ func MustLoad() *Config {
 		panic("config file not found: " + path)
 	}
 
-	var cfg Config
-
-	if err := cleanenv.ReadConfig(path, &cfg); err != nil {
+	if err := cleanenv.ReadConfig(path, cfg); err != nil {
 		panic("failed to read config: " + err.Error())
 	}
-
-	return &cfg
 }
 
 func fetchConfigPath() string {
@@ -74,3 +251,72 @@ func fetchConfigPath() string {
 
 	return res
 }
+
+func (cfg *Config) validate() error {
+	if cfg.Translation.MaxBatchChars <= 0 {
+		return fmt.Errorf("TRANSLATION_MAX_BATCH_CHARS must be positive")
+	}
+
+	if cfg.Translation.DailyQuotaChars < 0 {
+		return fmt.Errorf("TRANSLATION_DAILY_QUOTA_CHARS must not be negative")
+	}
+
+	if cfg.Jobs.Workers < 1 {
+		return fmt.Errorf("JOBS_WORKERS must be positive")
+	}
+
+	if cfg.Jobs.MaxAttempts < 1 {
+		return fmt.Errorf("JOBS_MAX_ATTEMPTS must be positive")
+	}
+
+	if cfg.Jobs.RetryDelay < 0 || cfg.Jobs.Retention < 0 {
+		return fmt.Errorf("JOBS_RETRY_DELAY and JOBS_RETENTION must not be negative")
+	}
+
+	if cfg.RateLimit.Enabled && (cfg.RateLimit.RPS <= 0 || cfg.RateLimit.Burst < 1) {
+		return fmt.Errorf("RATE_LIMIT_RPS and RATE_LIMIT_BURST must be positive")
+	}
+
+	if cfg.RateLimit.Enabled && cfg.Auth.Enabled && (cfg.RateLimit.IPRPS <= 0 || cfg.RateLimit.IPBurst < 1) {
+		return fmt.Errorf("RATE_LIMIT_IP_RPS and RATE_LIMIT_IP_BURST must be positive")
+	}
+
+	switch cfg.Translation.Backend {
+	case TranslationBackendYandex:
+		if cfg.YandexTranslatorAPI.Key == "" {
+			return fmt.Errorf("TRANSLATOR_API_KEY is required for the %s translation backend",
+				TranslationBackendYandex)
+		}
+	case TranslationBackendLibreTranslate:
+	default:
+		return fmt.Errorf("unknown translation backend %q", cfg.Translation.Backend)
+	}
+
+	switch cfg.Storage.Backend {
+	case StorageBackendMemory:
+		if cfg.Auth.Enabled {
+			return fmt.Errorf("AUTH_ENABLED=true is not supported by the %s storage backend: "+
+				"cmd/apikeys stores keys in Postgres only, set AUTH_ENABLED=false", StorageBackendMemory)
+		}
+
+		return nil
+	case StorageBackendPostgres:
+	default:
+		return fmt.Errorf("unknown storage backend %q", cfg.Storage.Backend)
+	}
+
+	if cfg.Cache.BreakerThreshold < 1 || cfg.Cache.BreakerCooldown <= 0 {
+		return fmt.Errorf("CACHE_BREAKER_THRESHOLD and CACHE_BREAKER_COOLDOWN must be positive")
+	}
+
+	if err := cfg.DB.validate(); err != nil {
+		return fmt.Errorf("%w for the %s storage backend", err, StorageBackendPostgres)
+	}
+
+	if cfg.Redis.Password == "" {
+		return fmt.Errorf("REDIS_PASSWORD is required for the %s storage backend",
+			StorageBackendPostgres)
+	}
+
+	return nil
+}
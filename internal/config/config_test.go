@@ -0,0 +1,26 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateMemoryBackendRejectsAuth(t *testing.T) {
+	cfg := Config{
+		Storage:     StorageConfig{Backend: StorageBackendMemory},
+		Translation: TranslationConfig{Backend: TranslationBackendLibreTranslate, MaxBatchChars: 1},
+		Jobs:        JobsConfig{Workers: 1, MaxAttempts: 1},
+		Auth:        AuthConfig{Enabled: true},
+	}
+
+	err := cfg.validate()
+	if err == nil || !strings.Contains(err.Error(), "AUTH_ENABLED") {
+		t.Fatalf("validate() error = %v, want AUTH_ENABLED rejected", err)
+	}
+
+	cfg.Auth.Enabled = false
+
+	if err := cfg.validate(); err != nil {
+		t.Errorf("validate() error = %v, want nil with authentication off", err)
+	}
+}
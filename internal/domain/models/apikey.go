@@ -0,0 +1,48 @@
+package models
+
+import "time"
+
+type Scope string
+
+const (
+	ScopeRead  Scope = "read"
+	ScopeWrite Scope = "write"
+	ScopeAdmin Scope = "admin"
+)
+
+// ParseScope reports whether s names a known scope.
+func ParseScope(s string) (Scope, bool) {
+	switch scope := Scope(s); scope {
+	case ScopeRead, ScopeWrite, ScopeAdmin:
+		return scope, true
+	default:
+		return "", false
+	}
+}
+
+// APIKey describes a client key. The key itself is shown once on creation,
+// only its hash is stored.
+type APIKey struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	// Prefix is the beginning of the key, to tell keys apart in listings.
+	Prefix    string     `json:"prefix"`
+	Scopes    []Scope    `json:"scopes"`
+	CreatedAt time.Time  `json:"created_at"`
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+}
+
+// HasScope reports whether the key grants scope. Admin grants every scope
+// and write grants read.
+func (k *APIKey) HasScope(scope Scope) bool {
+	for _, granted := range k.Scopes {
+		switch {
+		case granted == scope, granted == ScopeAdmin:
+			return true
+		case granted == ScopeWrite && scope == ScopeRead:
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,40 @@
+package models
+
+import "time"
+
+type HealthStatus string
+
+const (
+	HealthUp      HealthStatus = "up"
+	HealthDown    HealthStatus = "down"
+	HealthUnknown HealthStatus = "unknown"
+)
+
+type ReadinessStatus string
+
+const (
+	// ReadinessOK means every component is up.
+	ReadinessOK ReadinessStatus = "ok"
+	// ReadinessDegraded means only optional components are down, the
+	// service still takes traffic.
+	ReadinessDegraded ReadinessStatus = "degraded"
+	// ReadinessUnavailable means a required component is down or hasn't
+	// been checked yet.
+	ReadinessUnavailable ReadinessStatus = "unavailable"
+)
+
+// ComponentHealth is the result of the last probe of a dependency.
+type ComponentHealth struct {
+	Status    HealthStatus `json:"status"`
+	Required  bool         `json:"required"`
+	LatencyMS int64        `json:"latency_ms"`
+	// Error is a short reason, timeout or unreachable, the full error is
+	// only logged.
+	Error     string     `json:"error,omitempty"`
+	CheckedAt *time.Time `json:"checked_at,omitempty"`
+}
+
+type Readiness struct {
+	Status     ReadinessStatus            `json:"status"`
+	Components map[string]ComponentHealth `json:"components"`
+}
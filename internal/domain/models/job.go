@@ -0,0 +1,27 @@
+package models
+
+import "time"
+
+type JobStatus string
+
+const (
+	JobPending   JobStatus = "pending"
+	JobRunning   JobStatus = "running"
+	JobSucceeded JobStatus = "succeeded"
+	JobFailed    JobStatus = "failed"
+)
+
+// Job is a request to save a track that is processed in the background.
+type Job struct {
+	ID          string    `json:"id"`
+	ClientID    string    `json:"-"`
+	Status      JobStatus `json:"status"`
+	Artist      string    `json:"artist"`
+	Title       string    `json:"title"`
+	TargetLangs []string  `json:"target_langs"`
+	TrackUUID   string    `json:"track_uuid,omitempty"`
+	Error       string    `json:"error,omitempty"`
+	Attempts    int       `json:"attempts"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
@@ -0,0 +1,6 @@
+package models
+
+type Lyrics struct {
+	Source string
+	Lines  []string
+}
@@ -0,0 +1,19 @@
+package models
+
+type TrackSort string
+
+const (
+	SortByTitle     TrackSort = "title"
+	SortByCreatedAt TrackSort = "created_at"
+)
+
+type PageQuery struct {
+	Limit  int
+	Cursor string
+	Sort   TrackSort
+}
+
+type TrackPage struct {
+	Tracks     []*Track `json:"tracks"`
+	NextCursor string   `json:"next_cursor,omitempty"`
+}
@@ -0,0 +1,20 @@
+package models
+
+type SearchQuery struct {
+	Query  string
+	Artist string
+	Limit  int
+}
+
+// SearchHit holds only the lines that matched the query, with the matched
+// words wrapped in <mark> tags. Translation comes from the best matching
+// language, which is stored in Language.
+type SearchHit struct {
+	UUID        string   `json:"uuid"`
+	Artist      string   `json:"artist"`
+	Title       string   `json:"title"`
+	Rank        float64  `json:"rank"`
+	Lyrics      []string `json:"lyrics"`
+	Language    string   `json:"language,omitempty"`
+	Translation []string `json:"translation"`
+}
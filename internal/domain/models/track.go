@@ -1,8 +1,68 @@
 package models
 
+import (
+	"slices"
+	"time"
+)
+
 type Track struct {
-	Title       string
-	Artist      string
-	Lyrics      []string
-	Translation []string
+	UUID   string   `json:"uuid"`
+	Title  string   `json:"title"`
+	Artist string   `json:"artist"`
+	Lyrics []string `json:"lyrics"`
+	// Translations maps a language code to the translated lyrics.
+	Translations map[string][]string `json:"translations"`
+	// ManualTranslations lists the languages whose translation has been
+	// corrected by hand. Machine translations never replace them.
+	ManualTranslations []string `json:"manual_translations,omitempty"`
+	Source             string   `json:"source"`
+	// Synced holds the lyrics with timings, if an LRC file has been uploaded.
+	Synced []SyncedLine `json:"synced,omitempty"`
+	// Version is incremented on every change of the stored track.
+	Version   int64     `json:"version"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// SyncedLine is a lyric line with the time it starts at, in milliseconds
+// from the beginning of the song.
+type SyncedLine struct {
+	TimeMS int64  `json:"time_ms"`
+	Text   string `json:"text"`
+}
+
+// TrackUpdate is a manual correction of a track. Nil and empty fields are
+// left as they are.
+type TrackUpdate struct {
+	Artist *string
+	Title  *string
+	Lyrics []LineEdit
+	// Translations maps a language code to the edits of its translation.
+	Translations map[string][]LineEdit
+}
+
+// LineEdit replaces the text of a line, numbered from 1.
+type LineEdit struct {
+	Line int    `json:"line"`
+	Text string `json:"text"`
+}
+
+// WithTranslation returns a copy of the track that keeps only the
+// translation into lang. ok is false if there is no such translation.
+func (t *Track) WithTranslation(lang string) (track *Track, ok bool) {
+	lines, ok := t.Translations[lang]
+
+	copied := *t
+	copied.Translations = map[string][]string{}
+	copied.ManualTranslations = nil
+
+	if ok {
+		copied.Translations[lang] = lines
+
+		if slices.Contains(t.ManualTranslations, lang) {
+			copied.ManualTranslations = []string{lang}
+		}
+	}
+
+	return &copied, ok
 }
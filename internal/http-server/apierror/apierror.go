@@ -0,0 +1,77 @@
+package apierror
+
+import (
+	"errors"
+	"net/http"
+
+	apiFormat "lyrics-library/internal/lib/api/format"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/lrc"
+	authService "lyrics-library/internal/service/auth"
+	jobService "lyrics-library/internal/service/job"
+	trackService "lyrics-library/internal/service/track"
+)
+
+type mapping struct {
+	err    error
+	status int
+	code   problem.Code
+	// wrapped sends the whole error as the detail instead of the sentinel's
+	// message, for errors that carry useful context and no internals.
+	wrapped bool
+}
+
+var mappings = []mapping{
+	{err: trackService.ErrTrackNotFound, status: http.StatusNotFound, code: problem.CodeTrackNotFound},
+	{err: trackService.ErrArtistTracksNotFound, status: http.StatusNotFound, code: problem.CodeArtistTracksNotFound},
+	{err: trackService.ErrLyricsNotFound, status: http.StatusNotFound, code: problem.CodeLyricsNotFound},
+	{err: trackService.ErrTrackExists, status: http.StatusConflict, code: problem.CodeTrackExists},
+	{err: trackService.ErrVersionMismatch, status: http.StatusPreconditionFailed, code: problem.CodeVersionMismatch},
+	{err: trackService.ErrInvalidUpdate, status: http.StatusUnprocessableEntity, code: problem.CodeValidationFailed},
+	{err: trackService.ErrInvalidCursor, status: http.StatusBadRequest, code: problem.CodeInvalidCursor},
+	{err: trackService.ErrQuotaExceeded, status: http.StatusTooManyRequests, code: problem.CodeQuotaExceeded},
+	{err: trackService.ErrFailedTranslateLyrics, status: http.StatusBadGateway, code: problem.CodeTranslationFailed},
+	{err: trackService.ErrUpstreamUnavailable, status: http.StatusBadGateway, code: problem.CodeUpstreamUnavailable},
+	{err: jobService.ErrJobNotFound, status: http.StatusNotFound, code: problem.CodeJobNotFound},
+	{err: authService.ErrInvalidKey, status: http.StatusUnauthorized, code: problem.CodeUnauthorized},
+	{err: apiFormat.ErrUnknownExtension, status: http.StatusNotFound, code: problem.CodeUnknownFormat},
+	{err: apiFormat.ErrNotAcceptable, status: http.StatusNotAcceptable, code: problem.CodeNotAcceptable},
+	{err: lrc.ErrInvalid, status: http.StatusUnprocessableEntity, code: problem.CodeInvalidLRC, wrapped: true},
+	{err: lrc.ErrEmpty, status: http.StatusUnprocessableEntity, code: problem.CodeInvalidLRC, wrapped: true},
+}
+
+// FromError maps the sentinel errors of services and libraries to problems.
+// Any other error is an internal error and its message is not exposed.
+func FromError(err error) *problem.Problem {
+	for _, m := range mappings {
+		if !errors.Is(err, m.err) {
+			continue
+		}
+
+		detail := m.err.Error()
+		if m.wrapped {
+			detail = err.Error()
+		}
+
+		p := problem.New(m.status, m.code, detail)
+
+		var quotaErr *trackService.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			p.RetryAfter = quotaErr.RetryAfter
+		}
+
+		var updateErr *trackService.InvalidUpdateError
+		if errors.As(err, &updateErr) {
+			p.Detail = updateErr.Reason
+		}
+
+		return p
+	}
+
+	return problem.New(http.StatusInternalServerError, problem.CodeInternal, "internal error")
+}
+
+// Write sends the problem err maps to.
+func Write(w http.ResponseWriter, r *http.Request, err error) {
+	problem.Write(w, r, FromError(err))
+}
@@ -0,0 +1,186 @@
+package apierror_test
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/http-server/apierror"
+	apiFormat "lyrics-library/internal/lib/api/format"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/lrc"
+	authService "lyrics-library/internal/service/auth"
+	jobService "lyrics-library/internal/service/job"
+	trackService "lyrics-library/internal/service/track"
+)
+
+func TestFromError(t *testing.T) {
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+		wantDetail string
+		wantRetry  time.Duration
+	}{
+		{
+			name:       "track not found",
+			err:        fmt.Errorf("service.track.Track: %w", trackService.ErrTrackNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeTrackNotFound,
+			wantDetail: trackService.ErrTrackNotFound.Error(),
+		},
+		{
+			name:       "artist's tracks not found",
+			err:        trackService.ErrArtistTracksNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeArtistTracksNotFound,
+			wantDetail: trackService.ErrArtistTracksNotFound.Error(),
+		},
+		{
+			name:       "lyrics not found",
+			err:        trackService.ErrLyricsNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeLyricsNotFound,
+			wantDetail: trackService.ErrLyricsNotFound.Error(),
+		},
+		{
+			name:       "job not found",
+			err:        jobService.ErrJobNotFound,
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeJobNotFound,
+			wantDetail: jobService.ErrJobNotFound.Error(),
+		},
+		{
+			name:       "unknown extension",
+			err:        apiFormat.ErrUnknownExtension,
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeUnknownFormat,
+			wantDetail: apiFormat.ErrUnknownExtension.Error(),
+		},
+		{
+			name:       "invalid key",
+			err:        authService.ErrInvalidKey,
+			wantStatus: http.StatusUnauthorized,
+			wantCode:   problem.CodeUnauthorized,
+			wantDetail: authService.ErrInvalidKey.Error(),
+		},
+		{
+			name:       "not acceptable",
+			err:        apiFormat.ErrNotAcceptable,
+			wantStatus: http.StatusNotAcceptable,
+			wantCode:   problem.CodeNotAcceptable,
+			wantDetail: apiFormat.ErrNotAcceptable.Error(),
+		},
+		{
+			name:       "invalid cursor",
+			err:        trackService.ErrInvalidCursor,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidCursor,
+			wantDetail: trackService.ErrInvalidCursor.Error(),
+		},
+		{
+			name:       "track exists",
+			err:        fmt.Errorf("service.track.Update: %w", trackService.ErrTrackExists),
+			wantStatus: http.StatusConflict,
+			wantCode:   problem.CodeTrackExists,
+			wantDetail: trackService.ErrTrackExists.Error(),
+		},
+		{
+			name:       "version mismatch",
+			err:        fmt.Errorf("service.track.Update: %w", trackService.ErrVersionMismatch),
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   problem.CodeVersionMismatch,
+			wantDetail: trackService.ErrVersionMismatch.Error(),
+		},
+		{
+			name:       "invalid update tells the reason",
+			err:        fmt.Errorf("service.track.Update: %w", &trackService.InvalidUpdateError{Reason: "title must not be empty"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeValidationFailed,
+			wantDetail: "title must not be empty",
+		},
+		{
+			name:       "invalid lrc tells the line",
+			err:        fmt.Errorf("line 3: %w", lrc.ErrInvalid),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidLRC,
+			wantDetail: "line 3: " + lrc.ErrInvalid.Error(),
+		},
+		{
+			name:       "empty lrc",
+			err:        lrc.ErrEmpty,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidLRC,
+			wantDetail: lrc.ErrEmpty.Error(),
+		},
+		{
+			name:       "quota exceeded tells when to retry",
+			err:        fmt.Errorf("service.track.Save: %w", &trackService.QuotaExceededError{RetryAfter: 90 * time.Second}),
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   problem.CodeQuotaExceeded,
+			wantDetail: trackService.ErrQuotaExceeded.Error(),
+			wantRetry:  90 * time.Second,
+		},
+		{
+			name:       "translation failed",
+			err:        trackService.ErrFailedTranslateLyrics,
+			wantStatus: http.StatusBadGateway,
+			wantCode:   problem.CodeTranslationFailed,
+			wantDetail: trackService.ErrFailedTranslateLyrics.Error(),
+		},
+		{
+			name:       "upstream unavailable hides the cause",
+			err:        fmt.Errorf("%w: %w", trackService.ErrUpstreamUnavailable, errors.New("dial tcp 10.0.0.1:443")),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   problem.CodeUpstreamUnavailable,
+			wantDetail: trackService.ErrUpstreamUnavailable.Error(),
+		},
+		{
+			name:       "anything else is internal",
+			err:        errors.New("pq: password authentication failed"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+			wantDetail: "internal error",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			p := apierror.FromError(tt.err)
+
+			if p.Status != tt.wantStatus || p.Code != tt.wantCode {
+				t.Errorf("FromError() = %d %s, want %d %s", p.Status, p.Code, tt.wantStatus, tt.wantCode)
+			}
+
+			if p.Detail != tt.wantDetail {
+				t.Errorf("FromError() detail = %q, want %q", p.Detail, tt.wantDetail)
+			}
+
+			if p.RetryAfter != tt.wantRetry {
+				t.Errorf("FromError() RetryAfter = %s, want %s", p.RetryAfter, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestWrite(t *testing.T) {
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/lyrics", nil)
+
+	apierror.Write(rec, req, &trackService.QuotaExceededError{RetryAfter: 1500 * time.Millisecond})
+
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want %q", got, "2")
+	}
+
+	p := problemtest.Check(t, rec.Result(), http.StatusTooManyRequests, problem.CodeQuotaExceeded)
+
+	if p.Instance != "/lyrics" {
+		t.Errorf("problem instance = %q, want %q", p.Instance, "/lyrics")
+	}
+}
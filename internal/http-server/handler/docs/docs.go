@@ -0,0 +1,41 @@
+package docs
+
+import (
+	"net/http"
+)
+
+const page = `<!DOCTYPE html>
+<html>
+<head>
+	<meta charset="utf-8">
+	<title>Lyrics Library API</title>
+	<meta name="viewport" content="width=device-width, initial-scale=1">
+	<link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+	<div id="swagger-ui"></div>
+	<script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+	<script>
+		window.ui = SwaggerUIBundle({url: "/openapi.json", dom_id: "#swagger-ui"});
+	</script>
+</body>
+</html>
+`
+
+func Spec(spec []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write(spec)
+	}
+}
+
+func Page() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.WriteHeader(http.StatusOK)
+
+		_, _ = w.Write([]byte(page))
+	}
+}
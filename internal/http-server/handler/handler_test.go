@@ -0,0 +1,445 @@
+package handler_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"lyrics-library/api"
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/docs"
+	"lyrics-library/internal/http-server/handler/health"
+	jobget "lyrics-library/internal/http-server/handler/jobs/get"
+	"lyrics-library/internal/http-server/handler/lyrics/byuuid"
+	del "lyrics-library/internal/http-server/handler/lyrics/delete"
+	"lyrics-library/internal/http-server/handler/lyrics/get"
+	"lyrics-library/internal/http-server/handler/lyrics/save"
+	"lyrics-library/internal/http-server/handler/lyrics/search"
+	"lyrics-library/internal/http-server/handler/lyrics/update"
+	"lyrics-library/internal/http-server/handler/lyrics/uploadlrc"
+	"lyrics-library/internal/http-server/middleware/requestid"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/background"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/lib/metrics"
+	healthService "lyrics-library/internal/service/health"
+	"lyrics-library/internal/service/job"
+	"lyrics-library/internal/service/track"
+	"lyrics-library/internal/storage/memory"
+)
+
+type providerFunc func(ctx context.Context, artist, title string) (*models.Lyrics, error)
+
+func (f providerFunc) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+	return f(ctx, artist, title)
+}
+
+type translatorFunc func(ctx context.Context, lyrics []string, targetLang string) ([]string, error)
+
+func (f translatorFunc) TranslateLyrics(ctx context.Context, lyrics []string, targetLang string) ([]string, error) {
+	return f(ctx, lyrics, targetLang)
+}
+
+// newServer wires the handlers the way main does, on top of the memory
+// backend. Lyrics are found for every song and translated by prefixing
+// each line with the language. Every response is checked against the
+// OpenAPI document.
+func newServer(t *testing.T) *httptest.Server {
+	t.Helper()
+
+	log := slogdiscard.NewDiscardLogger()
+
+	storage := memory.New()
+	bg := background.New(time.Second)
+
+	t.Cleanup(func() { _ = bg.Shutdown(context.Background()) })
+
+	provider := providerFunc(func(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+		return &models.Lyrics{Lines: []string{"first line of " + title, "second line"}, Source: "test"}, nil
+	})
+
+	translator := translatorFunc(func(ctx context.Context, lyrics []string, targetLang string) ([]string, error) {
+		translated := make([]string, len(lyrics))
+		for i, line := range lyrics {
+			translated[i] = targetLang + ": " + line
+		}
+
+		return translated, nil
+	})
+
+	trackService := track.New(log, provider, translator, storage,
+		memory.NewCache(100, time.Minute, time.Minute, nil), []string{"de"}, bg)
+
+	jobService := job.New(log, storage, trackService, 1, time.Second, time.Minute, 3, 0, 0)
+
+	router := chi.NewRouter()
+
+	router.Use(requestid.New())
+	router.Use(middleware.URLFormat)
+
+	router.Route("/lyrics", func(r chi.Router) {
+		r.Post("/", save.New(log, trackService, jobService))
+		r.Get("/", get.New(log, trackService, trackService))
+		r.Get("/search", search.New(log, trackService))
+		r.Get("/{uuid}", byuuid.New(log, trackService))
+		r.Patch("/{uuid}", update.New(log, trackService))
+		r.Delete("/{uuid}", del.New(log, trackService))
+		r.Put("/{uuid}/lrc", uploadlrc.New(log, trackService))
+	})
+
+	router.Get("/jobs/{id}", jobget.New(log, jobService))
+
+	router.Get("/openapi", docs.Spec(api.OpenAPI))
+	router.Get("/docs", docs.Page())
+	router.Handle("/metrics", metrics.New().Handler())
+	router.Get("/healthz", health.Live())
+	router.Get("/readyz", health.Ready(healthService.New(log, time.Minute, time.Second)))
+
+	srv := httptest.NewServer(router)
+	t.Cleanup(srv.Close)
+
+	client := srv.Client()
+	client.Transport = &specTransport{t: t, spec: loadSpec(t), base: client.Transport, seen: make(map[string]bool)}
+
+	return srv
+}
+
+func do(t *testing.T, srv *httptest.Server, method, path, body string, header http.Header) *http.Response {
+	t.Helper()
+
+	req, err := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("failed to create request: %v", err)
+	}
+
+	for key, values := range header {
+		req.Header[key] = values
+	}
+
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("%s %s: %v", method, path, err)
+	}
+
+	t.Cleanup(func() { _ = resp.Body.Close() })
+
+	return resp
+}
+
+func decode[T any](t *testing.T, resp *http.Response) T {
+	t.Helper()
+
+	var v T
+	if err := json.NewDecoder(resp.Body).Decode(&v); err != nil {
+		t.Fatalf("failed to decode response: %v", err)
+	}
+
+	return v
+}
+
+func checkStatus(t *testing.T, resp *http.Response, want int) {
+	t.Helper()
+
+	if resp.StatusCode != want {
+		body, _ := io.ReadAll(resp.Body)
+
+		t.Fatalf("%s %s: status = %d, want %d: %s", resp.Request.Method, resp.Request.URL.Path,
+			resp.StatusCode, want, body)
+	}
+}
+
+func saveTrack(t *testing.T, srv *httptest.Server, artist, title string) *models.Track {
+	t.Helper()
+
+	body, _ := json.Marshal(save.Request{Artist: artist, Title: title})
+
+	resp := do(t, srv, http.MethodPost, "/lyrics", string(body), nil)
+	checkStatus(t, resp, http.StatusCreated)
+
+	return decode[*models.Track](t, resp)
+}
+
+func TestSaveAndRead(t *testing.T) {
+	srv := newServer(t)
+
+	saved := saveTrack(t, srv, "Daft Punk", "Around the World")
+
+	if saved.Version != 1 || !slices.Equal(saved.Lyrics, []string{"first line of Around the World", "second line"}) {
+		t.Fatalf("saved track = %+v", saved)
+	}
+
+	if got := saved.Translations["de"]; len(got) != 2 || got[0] != "de: first line of Around the World" {
+		t.Errorf("saved translations = %q, want the default language", saved.Translations)
+	}
+
+	resp := do(t, srv, http.MethodPost, "/lyrics", `{"artist":"daft punk","title":"AROUND THE WORLD"}`, nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if again := decode[*models.Track](t, resp); again.UUID != saved.UUID {
+		t.Errorf("saving the same song again returned %s, want %s", again.UUID, saved.UUID)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics?artist=DAFT+PUNK&title=around+the+world", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if got := resp.Header.Get("ETag"); got != `"1"` {
+		t.Errorf("ETag = %s, want \"1\"", got)
+	}
+
+	if got := decode[*models.Track](t, resp); got.UUID != saved.UUID {
+		t.Errorf("GET /lyrics returned %s, want %s", got.UUID, saved.UUID)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID+"?lang=de", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if got := decode[*models.Track](t, resp); len(got.Translations) != 1 || got.Translations["de"] == nil {
+		t.Errorf("GET /lyrics/{uuid}?lang=de translations = %q, want only de", got.Translations)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID+".txt", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if got, _ := io.ReadAll(resp.Body); !strings.Contains(string(got), "first line of Around the World") {
+		t.Errorf("GET /lyrics/{uuid}.txt = %q", got)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID+"?lang=fr", "", nil)
+
+	if p := problemtest.Check(t, resp, http.StatusNotFound, problem.CodeTranslationNotFound); p.RequestID == "" {
+		t.Error("problem has no request id")
+	}
+}
+
+func TestETags(t *testing.T) {
+	srv := newServer(t)
+
+	saved := saveTrack(t, srv, "Daft Punk", "Aerodynamic")
+
+	representations := []struct {
+		path   string
+		accept string
+		want   string
+	}{
+		{path: "/lyrics/" + saved.UUID, want: `"1"`},
+		{path: "/lyrics/" + saved.UUID + "?lang=de", want: `"1-de"`},
+		{path: "/lyrics/" + saved.UUID + ".txt", want: `"1-txt"`},
+		{path: "/lyrics/" + saved.UUID, accept: "text/csv", want: `"1-csv"`},
+		{path: "/lyrics?artist=daft+punk&title=aerodynamic&lang=de", accept: "text/markdown", want: `"1-md-de"`},
+	}
+
+	for _, rep := range representations {
+		var header http.Header
+		if rep.accept != "" {
+			header = http.Header{"Accept": {rep.accept}}
+		}
+
+		resp := do(t, srv, http.MethodGet, rep.path, "", header)
+		checkStatus(t, resp, http.StatusOK)
+
+		if got := resp.Header.Get("ETag"); got != rep.want {
+			t.Errorf("GET %s (Accept %q) ETag = %s, want %s", rep.path, rep.accept, got, rep.want)
+		}
+
+		if got := resp.Header.Get("Vary"); got != "Accept" {
+			t.Errorf("GET %s Vary = %q, want Accept", rep.path, got)
+		}
+	}
+
+	// The tag of any representation names the version an edit is based on.
+	resp := do(t, srv, http.MethodPatch, "/lyrics/"+saved.UUID, `{"title":"Aerodynamite"}`,
+		http.Header{"If-Match": {`"1-txt"`}})
+	checkStatus(t, resp, http.StatusOK)
+
+	resp = do(t, srv, http.MethodPatch, "/lyrics/"+saved.UUID, `{"title":"Aerodynamic"}`,
+		http.Header{"If-Match": {`"1-txt"`}})
+	problemtest.Check(t, resp, http.StatusPreconditionFailed, problem.CodeVersionMismatch)
+}
+
+func TestArtistTracks(t *testing.T) {
+	srv := newServer(t)
+
+	for _, title := range []string{"One More Time", "Aerodynamic", "Digital Love"} {
+		saveTrack(t, srv, "Daft Punk", title)
+	}
+
+	saveTrack(t, srv, "Justice", "Genesis")
+
+	var titles []string
+
+	path := "/lyrics?artist=daft+punk&limit=2"
+	for path != "" {
+		resp := do(t, srv, http.MethodGet, path, "", nil)
+		checkStatus(t, resp, http.StatusOK)
+
+		page := decode[models.TrackPage](t, resp)
+		for _, track := range page.Tracks {
+			titles = append(titles, track.Title)
+		}
+
+		path = ""
+		if page.NextCursor != "" {
+			path = "/lyrics?artist=daft+punk&limit=2&cursor=" + url.QueryEscape(page.NextCursor)
+		}
+	}
+
+	want := []string{"Aerodynamic", "Digital Love", "One More Time"}
+	if !slices.Equal(titles, want) {
+		t.Errorf("artist's tracks = %q, want %q", titles, want)
+	}
+
+	resp := do(t, srv, http.MethodGet, "/lyrics?artist=daft+punk&cursor=nope", "", nil)
+	problemtest.Check(t, resp, http.StatusBadRequest, problem.CodeInvalidCursor)
+
+	resp = do(t, srv, http.MethodGet, "/lyrics?artist=Air", "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeArtistTracksNotFound)
+}
+
+func TestSearch(t *testing.T) {
+	srv := newServer(t)
+
+	saveTrack(t, srv, "Daft Punk", "Aerodynamic")
+	saveTrack(t, srv, "Justice", "Genesis")
+
+	resp := do(t, srv, http.MethodGet, "/lyrics/search?q=genesis", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	results := decode[search.Response](t, resp).Results
+	if len(results) != 1 || results[0].Title != "Genesis" {
+		t.Errorf("search results = %+v, want Genesis", results)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics/search?q=nothing+like+this", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if results := decode[search.Response](t, resp).Results; results == nil || len(results) != 0 {
+		t.Errorf("search results = %+v, want an empty list", results)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	srv := newServer(t)
+
+	saved := saveTrack(t, srv, "Daft Punk", "Aerodynamic")
+	other := saveTrack(t, srv, "Daft Punk", "Digital Love")
+
+	resp := do(t, srv, http.MethodPatch, "/lyrics/"+saved.UUID, `{"title":"Aerodynamite"}`,
+		http.Header{"If-Match": {`"7"`}})
+	problemtest.Check(t, resp, http.StatusPreconditionFailed, problem.CodeVersionMismatch)
+
+	resp = do(t, srv, http.MethodPatch, "/lyrics/"+saved.UUID, `{"title":"Aerodynamite","lyrics":[{"line":1,"text":"fixed"}]}`,
+		http.Header{"If-Match": {`"1"`}})
+	checkStatus(t, resp, http.StatusOK)
+
+	if got := resp.Header.Get("ETag"); got != `"2"` {
+		t.Errorf("ETag = %s, want \"2\"", got)
+	}
+
+	updated := decode[*models.Track](t, resp)
+	if updated.Title != "Aerodynamite" || updated.Lyrics[0] != "fixed" || updated.Version != 2 {
+		t.Errorf("updated track = %+v", updated)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics?artist=daft+punk&title=aerodynamite", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	resp = do(t, srv, http.MethodGet, "/lyrics?artist=daft+punk&title=aerodynamic", "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeTrackNotFound)
+
+	resp = do(t, srv, http.MethodPatch, "/lyrics/"+other.UUID, `{"title":"aerodynamite"}`,
+		http.Header{"If-Match": {"*"}})
+	problemtest.Check(t, resp, http.StatusConflict, problem.CodeTrackExists)
+
+	// Edits of a translation the track doesn't have are a bad request
+	// body, not a missing resource.
+	for _, body := range []string{
+		`{"translations":{"fr":[{"line":1,"text":"corrigé"}]}}`,
+		`{"translations":{"de":[{"line":99,"text":"korrigiert"}]}}`,
+	} {
+		resp = do(t, srv, http.MethodPatch, "/lyrics/"+other.UUID, body, http.Header{"If-Match": {"*"}})
+		problemtest.Check(t, resp, http.StatusUnprocessableEntity, problem.CodeValidationFailed)
+	}
+}
+
+func TestUploadLRC(t *testing.T) {
+	srv := newServer(t)
+
+	saved := saveTrack(t, srv, "Daft Punk", "Aerodynamic")
+
+	resp := do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID+".lrc", "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeSyncedLyricsNotFound)
+
+	resp = do(t, srv, http.MethodPut, "/lyrics/"+saved.UUID+"/lrc", "[00:02.00]second\n[00:01.00]first\n", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	synced := decode[*models.Track](t, resp).Synced
+	if len(synced) != 2 || synced[0].Text != "first" || synced[0].TimeMS != 1000 {
+		t.Errorf("synced lyrics = %+v", synced)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID+".lrc", "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if got, _ := io.ReadAll(resp.Body); !strings.Contains(string(got), "[00:01.000]first") {
+		t.Errorf("GET /lyrics/{uuid}.lrc = %q", got)
+	}
+
+	resp = do(t, srv, http.MethodPut, "/lyrics/"+saved.UUID+"/lrc", "not an lrc file\n", nil)
+	problemtest.Check(t, resp, http.StatusUnprocessableEntity, problem.CodeInvalidLRC)
+}
+
+func TestDelete(t *testing.T) {
+	srv := newServer(t)
+
+	saved := saveTrack(t, srv, "Daft Punk", "Aerodynamic")
+
+	// Reading fills the cache, the delete must not leave the track there.
+	checkStatus(t, do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID, "", nil), http.StatusOK)
+	checkStatus(t, do(t, srv, http.MethodGet, "/lyrics?artist=daft+punk", "", nil), http.StatusOK)
+
+	resp := do(t, srv, http.MethodDelete, "/lyrics/"+saved.UUID, "", nil)
+	checkStatus(t, resp, http.StatusNoContent)
+
+	resp = do(t, srv, http.MethodGet, "/lyrics/"+saved.UUID, "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeTrackNotFound)
+
+	resp = do(t, srv, http.MethodGet, "/lyrics?artist=daft+punk", "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeArtistTracksNotFound)
+
+	resp = do(t, srv, http.MethodDelete, "/lyrics/"+saved.UUID, "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeTrackNotFound)
+}
+
+func TestSaveAsync(t *testing.T) {
+	srv := newServer(t)
+
+	resp := do(t, srv, http.MethodPost, "/lyrics?async=true", `{"artist":"Justice","title":"Genesis"}`, nil)
+	checkStatus(t, resp, http.StatusAccepted)
+
+	created := decode[*models.Job](t, resp)
+
+	if got := resp.Header.Get("Location"); got != "/jobs/"+created.ID {
+		t.Errorf("Location = %q, want /jobs/%s", got, created.ID)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/jobs/"+created.ID, "", nil)
+	checkStatus(t, resp, http.StatusOK)
+
+	if got := decode[*models.Job](t, resp); got.ID != created.ID || got.Status != models.JobPending {
+		t.Errorf("job = %+v, want it pending", got)
+	}
+
+	resp = do(t, srv, http.MethodGet, "/jobs/0b4e6a52-5a5c-4c43-9f0e-4f4f2d3a8c11", "", nil)
+	problemtest.Check(t, resp, http.StatusNotFound, problem.CodeJobNotFound)
+}
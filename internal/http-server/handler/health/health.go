@@ -0,0 +1,43 @@
+package health
+
+import (
+	"net/http"
+
+	"github.com/go-chi/render"
+
+	"lyrics-library/internal/domain/models"
+)
+
+type ReadinessProvider interface {
+	Readiness() *models.Readiness
+}
+
+type liveResponse struct {
+	Status string `json:"status"`
+}
+
+// Live reports that the process is able to serve requests, it doesn't
+// look at any dependency.
+func Live() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		render.Status(r, http.StatusOK)
+
+		render.JSON(w, r, liveResponse{Status: "ok"})
+	}
+}
+
+// Ready returns the cached probe results, with 503 while a required
+// component is down.
+func Ready(readinessProvider ReadinessProvider) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		readiness := readinessProvider.Readiness()
+
+		if readiness.Status == models.ReadinessUnavailable {
+			render.Status(r, http.StatusServiceUnavailable)
+		} else {
+			render.Status(r, http.StatusOK)
+		}
+
+		render.JSON(w, r, readiness)
+	}
+}
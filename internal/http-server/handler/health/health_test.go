@@ -0,0 +1,68 @@
+package health_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/health"
+)
+
+type readinessFunc func() *models.Readiness
+
+func (f readinessFunc) Readiness() *models.Readiness {
+	return f()
+}
+
+func TestReady(t *testing.T) {
+	tests := []struct {
+		status     models.ReadinessStatus
+		wantStatus int
+	}{
+		{status: models.ReadinessOK, wantStatus: http.StatusOK},
+		{status: models.ReadinessDegraded, wantStatus: http.StatusOK},
+		{status: models.ReadinessUnavailable, wantStatus: http.StatusServiceUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			handler := health.Ready(readinessFunc(func() *models.Readiness {
+				return &models.Readiness{
+					Status: tt.status,
+					Components: map[string]models.ComponentHealth{
+						"redis": {Status: models.HealthDown, Error: "timeout"},
+					},
+				}
+			}))
+
+			rec := httptest.NewRecorder()
+
+			handler(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			var got models.Readiness
+			if err := json.NewDecoder(rec.Body).Decode(&got); err != nil {
+				t.Fatalf("decode body: %v", err)
+			}
+
+			if got.Status != tt.status || got.Components["redis"].Error != "timeout" {
+				t.Errorf("body = %+v", got)
+			}
+		})
+	}
+}
+
+func TestLive(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	health.Live()(rec, httptest.NewRequest(http.MethodGet, "/healthz", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
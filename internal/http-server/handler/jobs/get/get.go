@@ -0,0 +1,59 @@
+package get
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+)
+
+type JobProvider interface {
+	Job(ctx context.Context, id string) (*models.Job, error)
+}
+
+func New(
+	log *slog.Logger,
+	jobProvider JobProvider,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.job.get.New"
+
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
+
+		log := log.With(slog.String("op", op))
+
+		log.InfoContext(ctx, "getting job")
+
+		id := chi.URLParam(r, "id")
+
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid job id", sl.Err(err))
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidUUID, "invalid job id"))
+			return
+		}
+
+		id = parsed.String()
+
+		job, err := jobProvider.Job(ctx, id)
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.JSON(w, r, job)
+	}
+}
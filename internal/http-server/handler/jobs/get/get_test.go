@@ -0,0 +1,101 @@
+package get_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/jobs/get"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	jobService "lyrics-library/internal/service/job"
+)
+
+const jobID = "0b4e6a52-5a5c-4c43-9f0e-4f4f2d3a8c11"
+
+type jobProviderFunc func(ctx context.Context, id string) (*models.Job, error)
+
+func (f jobProviderFunc) Job(ctx context.Context, id string) (*models.Job, error) {
+	return f(ctx, id)
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "invalid id",
+			id:         "nope",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidUUID,
+		},
+		{
+			name:       "job not found",
+			id:         jobID,
+			err:        fmt.Errorf("service.job.Job: %w", jobService.ErrJobNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeJobNotFound,
+		},
+		{
+			name:       "unexpected error",
+			id:         jobID,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Get("/jobs/{id}", get.New(slogdiscard.NewDiscardLogger(),
+				jobProviderFunc(func(context.Context, string) (*models.Job, error) {
+					if tt.err == nil {
+						t.Error("Job() is called for an invalid request")
+					}
+
+					return nil, tt.err
+				}),
+			))
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/"+tt.id, nil))
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestGetCanonicalID(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/jobs/{id}", get.New(slogdiscard.NewDiscardLogger(),
+		jobProviderFunc(func(ctx context.Context, id string) (*models.Job, error) {
+			if id != jobID {
+				t.Errorf("Job() id = %q, want %q", id, jobID)
+			}
+
+			return &models.Job{ID: id, Status: models.JobPending}, nil
+		}),
+	))
+
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/jobs/"+strings.ToUpper(jobID), nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,75 @@
+package byuuid
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/apierror"
+	apiFormat "lyrics-library/internal/lib/api/format"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/lang"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+)
+
+type TrackProvider interface {
+	TrackByUUID(ctx context.Context, uuid string) (*models.Track, error)
+}
+
+func New(
+	log *slog.Logger,
+	trackProvider TrackProvider,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.song.byuuid.New"
+
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
+
+		log := log.With(slog.String("op", op))
+
+		log.InfoContext(ctx, "getting track by uuid")
+
+		id := chi.URLParam(r, "uuid")
+
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid uuid", sl.Err(err))
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidUUID, "invalid uuid"))
+			return
+		}
+
+		id = parsed.String()
+
+		format, err := apiFormat.Negotiate(r)
+		if err != nil {
+			log.ErrorContext(ctx, "unsupported format", sl.Err(err))
+
+			apierror.Write(w, r, err)
+			return
+		}
+
+		translationLang := lang.Normalize(r.URL.Query().Get("lang"))
+
+		if translationLang != "" && !lang.Valid(translationLang) {
+			log.ErrorContext(ctx, "invalid 'lang' parameter", slog.String("lang", translationLang))
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidLang, "invalid lang"))
+			return
+		}
+
+		track, err := trackProvider.TrackByUUID(ctx, id)
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+
+		apiFormat.RenderTrack(w, r, log, track, format, translationLang)
+	}
+}
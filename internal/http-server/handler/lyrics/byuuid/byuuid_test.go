@@ -0,0 +1,120 @@
+package byuuid_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/lyrics/byuuid"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	trackService "lyrics-library/internal/service/track"
+)
+
+const trackUUID = "0b4e6a52-5a5c-4c43-9f0e-4f4f2d3a8c11"
+
+type trackProviderFunc func(ctx context.Context, uuid string) (*models.Track, error)
+
+func (f trackProviderFunc) TrackByUUID(ctx context.Context, uuid string) (*models.Track, error) {
+	return f(ctx, uuid)
+}
+
+func TestByUUIDErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		accept     string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "invalid uuid",
+			target:     "/lyrics/nope",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidUUID,
+		},
+		{
+			name:       "invalid lang",
+			target:     "/lyrics/" + trackUUID + "?lang=nope!",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidLang,
+		},
+		{
+			name:       "not acceptable",
+			target:     "/lyrics/" + trackUUID,
+			accept:     "image/png",
+			wantStatus: http.StatusNotAcceptable,
+			wantCode:   problem.CodeNotAcceptable,
+		},
+		{
+			name:       "track not found",
+			target:     "/lyrics/" + trackUUID,
+			err:        fmt.Errorf("service.track.TrackByUUID: %w", trackService.ErrTrackNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeTrackNotFound,
+		},
+		{
+			name:       "unexpected error",
+			target:     "/lyrics/" + trackUUID,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Get("/lyrics/{uuid}", byuuid.New(slogdiscard.NewDiscardLogger(),
+				trackProviderFunc(func(context.Context, string) (*models.Track, error) {
+					if tt.err == nil {
+						t.Error("TrackByUUID() is called for an invalid request")
+					}
+
+					return nil, tt.err
+				}),
+			))
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestByUUIDCanonicalUUID(t *testing.T) {
+	router := chi.NewRouter()
+	router.Get("/lyrics/{uuid}", byuuid.New(slogdiscard.NewDiscardLogger(),
+		trackProviderFunc(func(ctx context.Context, uuid string) (*models.Track, error) {
+			if uuid != trackUUID {
+				t.Errorf("TrackByUUID() uuid = %q, want %q", uuid, trackUUID)
+			}
+
+			return &models.Track{UUID: uuid, Version: 1}, nil
+		}),
+	))
+
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/lyrics/urn:uuid:"+strings.ToUpper(trackUUID), nil))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
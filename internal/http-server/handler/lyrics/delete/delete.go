@@ -2,52 +2,53 @@ package delete
 
 import (
 	"context"
-	"errors"
 	"log/slog"
 	"net/http"
 
 	"github.com/go-chi/chi/v5"
-	"github.com/go-chi/render"
+	"github.com/google/uuid"
 
-	resp "lyrics-library/internal/lib/api/response"
-	"lyrics-library/internal/service/track"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
 )
 
 type TrackDeleter interface {
 	Delete(ctx context.Context, uuid string) error
 }
 
-func New(ctx context.Context,
+func New(
 	log *slog.Logger,
 	trackDeleter TrackDeleter,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.song.delete.New"
 
-		log = log.With("op", op)
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
 
-		log.Info("deleting track")
+		log := log.With("op", op)
 
-		uuid := chi.URLParam(r, "uuid")
+		log.InfoContext(ctx, "deleting track")
 
-		if uuid == "" {
-			log.Error("uuid is required")
+		id := chi.URLParam(r, "uuid")
 
-			w.WriteHeader(http.StatusBadRequest)
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid uuid", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidUUID, "invalid uuid"))
 			return
 		}
 
-		if err := trackDeleter.Delete(ctx, uuid); err != nil {
-			if errors.Is(err, track.ErrInvalidUUID) {
-				w.WriteHeader(http.StatusBadRequest)
+		id = parsed.String()
 
-				render.JSON(w, r, resp.Error("invalid uuid"))
-				return
-			}
+		if err := trackDeleter.Delete(ctx, id); err != nil {
+			apierror.Write(w, r, err)
+			return
 		}
 
-		w.WriteHeader(http.StatusOK)
+		w.WriteHeader(http.StatusNoContent)
 	}
 }
@@ -0,0 +1,112 @@
+package delete_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	del "lyrics-library/internal/http-server/handler/lyrics/delete"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	trackService "lyrics-library/internal/service/track"
+)
+
+const trackUUID = "0b4e6a52-5a5c-4c43-9f0e-4f4f2d3a8c11"
+
+type trackDeleterFunc func(ctx context.Context, uuid string) error
+
+func (f trackDeleterFunc) Delete(ctx context.Context, uuid string) error {
+	return f(ctx, uuid)
+}
+
+func TestDelete(t *testing.T) {
+	ids := []string{
+		trackUUID,
+		strings.ToUpper(trackUUID),
+		"{" + trackUUID + "}",
+		"urn:uuid:" + trackUUID,
+		strings.ReplaceAll(trackUUID, "-", ""),
+	}
+
+	for _, id := range ids {
+		t.Run(id, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Delete("/lyrics/{uuid}", del.New(slogdiscard.NewDiscardLogger(),
+				trackDeleterFunc(func(ctx context.Context, uuid string) error {
+					if uuid != trackUUID {
+						t.Errorf("Delete() uuid = %q, want %q", uuid, trackUUID)
+					}
+
+					return nil
+				}),
+			))
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/lyrics/"+id, nil))
+
+			if rec.Code != http.StatusNoContent {
+				t.Errorf("status = %d, want %d", rec.Code, http.StatusNoContent)
+			}
+		})
+	}
+}
+
+func TestDeleteErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "invalid uuid",
+			id:         "nope",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidUUID,
+		},
+		{
+			name:       "track not found",
+			id:         trackUUID,
+			err:        fmt.Errorf("service.track.Delete: %w", trackService.ErrTrackNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeTrackNotFound,
+		},
+		{
+			name:       "unexpected error",
+			id:         trackUUID,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Delete("/lyrics/{uuid}", del.New(slogdiscard.NewDiscardLogger(),
+				trackDeleterFunc(func(context.Context, string) error {
+					if tt.err == nil {
+						t.Error("Delete() is called for an invalid request")
+					}
+
+					return tt.err
+				}),
+			))
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodDelete, "/lyrics/"+tt.id, nil))
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
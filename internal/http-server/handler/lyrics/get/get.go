@@ -3,14 +3,22 @@ package get
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"net/url"
+	"strconv"
 
 	"github.com/go-chi/render"
 
 	"lyrics-library/internal/domain/models"
-	resp "lyrics-library/internal/lib/api/response"
-	trackService "lyrics-library/internal/service/track"
+	"lyrics-library/internal/http-server/apierror"
+	apiFormat "lyrics-library/internal/lib/api/format"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/lang"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/pagination"
+	"lyrics-library/internal/lib/tracing"
 )
 
 type TrackProvider interface {
@@ -18,10 +26,10 @@ type TrackProvider interface {
 }
 
 type ArtistTracksProvider interface {
-	ArtistTracks(ctx context.Context, artist string) ([]*models.Track, error)
+	ArtistTracks(ctx context.Context, artist string, query models.PageQuery) (*models.TrackPage, error)
 }
 
-func New(ctx context.Context,
+func New(
 	log *slog.Logger,
 	trackProvider TrackProvider,
 	artistTracksProvider ArtistTracksProvider,
@@ -29,9 +37,12 @@ func New(ctx context.Context,
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.song.read.New"
 
-		log = log.With(slog.String("op", op))
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
 
-		log.Info("getting lyrics")
+		log := log.With(slog.String("op", op))
+
+		log.InfoContext(ctx, "getting lyrics")
 
 		query := r.URL.Query()
 
@@ -39,52 +50,104 @@ func New(ctx context.Context,
 		title := query.Get("title")
 
 		if artist == "" {
-			log.Error("missing 'artist' parameter")
+			log.ErrorContext(ctx, "missing 'artist' parameter")
 
-			w.WriteHeader(http.StatusBadRequest)
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "artist is required"))
+			return
+		}
+
+		format, err := apiFormat.Negotiate(r)
+		if err != nil {
+			log.ErrorContext(ctx, "unsupported format", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("artist is required"))
+			apierror.Write(w, r, err)
+			return
+		}
+
+		translationLang := lang.Normalize(query.Get("lang"))
+
+		if translationLang != "" && !lang.Valid(translationLang) {
+			log.ErrorContext(ctx, "invalid 'lang' parameter", slog.String("lang", translationLang))
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidLang, "invalid lang"))
 			return
 		}
 
 		if title == "" {
-			tracks, err := artistTracksProvider.ArtistTracks(ctx, artist)
-			if err != nil {
-				if errors.Is(err, trackService.ErrArtistTracksNotFound) {
-					w.WriteHeader(http.StatusBadRequest)
+			if format != apiFormat.JSON {
+				log.ErrorContext(ctx, "format is not supported for artist's tracks", slog.String("format", format))
 
-					render.JSON(w, r, resp.Error("artist's tracks not found"))
-					return
-				}
+				problem.Write(w, r, problem.New(http.StatusNotAcceptable, problem.CodeNotAcceptable, "only json is supported for artist's tracks"))
+				return
+			}
 
-				w.WriteHeader(http.StatusInternalServerError)
+			pageQuery, err := parsePageQuery(query)
+			if err != nil {
+				log.ErrorContext(ctx, "invalid pagination parameters", sl.Err(err))
 
-				render.JSON(w, r, resp.Error("internal error"))
+				problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, err.Error()))
 				return
 			}
 
-			w.WriteHeader(http.StatusOK)
+			page, err := artistTracksProvider.ArtistTracks(ctx, artist, pageQuery)
+			if err != nil {
+				apierror.Write(w, r, err)
+				return
+			}
+
+			// tracks is never null, even past the last page.
+			tracks := make([]*models.Track, 0, len(page.Tracks))
+
+			for _, track := range page.Tracks {
+				if translationLang != "" {
+					track, _ = track.WithTranslation(translationLang)
+				}
 
-			render.JSON(w, r, tracks)
+				tracks = append(tracks, track)
+			}
+
+			render.Status(r, http.StatusOK)
+
+			render.JSON(w, r, &models.TrackPage{
+				Tracks:     tracks,
+				NextCursor: page.NextCursor,
+			})
 			return
 		}
 
 		track, err := trackProvider.Track(ctx, artist, title)
 		if err != nil {
-			if errors.Is(err, trackService.ErrTrackNotFound) {
-				w.WriteHeader(http.StatusBadRequest)
+			apierror.Write(w, r, err)
+			return
+		}
 
-				render.JSON(w, r, resp.Error("track not found"))
-				return
-			}
+		apiFormat.RenderTrack(w, r, log, track, format, translationLang)
+	}
+}
 
-			w.WriteHeader(http.StatusInternalServerError)
+func parsePageQuery(query url.Values) (models.PageQuery, error) {
+	page := models.PageQuery{
+		Limit:  pagination.DefaultLimit,
+		Cursor: query.Get("cursor"),
+		Sort:   models.SortByTitle,
+	}
 
-			render.JSON(w, r, resp.Error("internal error"))
+	if limit := query.Get("limit"); limit != "" {
+		n, err := strconv.Atoi(limit)
+		if err != nil || n < 1 || n > pagination.MaxLimit {
+			return page, fmt.Errorf("limit must be between 1 and %d", pagination.MaxLimit)
 		}
 
-		w.WriteHeader(http.StatusOK)
+		page.Limit = n
+	}
 
-		render.JSON(w, r, track)
+	switch sort := models.TrackSort(query.Get("sort")); sort {
+	case "":
+	case models.SortByTitle, models.SortByCreatedAt:
+		page.Sort = sort
+	default:
+		return page, errors.New("sort must be one of: title, created_at")
 	}
+
+	return page, nil
 }
@@ -0,0 +1,167 @@
+package get_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/lyrics/get"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	trackService "lyrics-library/internal/service/track"
+)
+
+type trackProviderFunc func(ctx context.Context, artist, title string) (*models.Track, error)
+
+func (f trackProviderFunc) Track(ctx context.Context, artist, title string) (*models.Track, error) {
+	return f(ctx, artist, title)
+}
+
+type artistTracksProviderFunc func(ctx context.Context, artist string, query models.PageQuery) (*models.TrackPage, error)
+
+func (f artistTracksProviderFunc) ArtistTracks(ctx context.Context, artist string, query models.PageQuery) (*models.TrackPage, error) {
+	return f(ctx, artist, query)
+}
+
+func TestGetErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		accept     string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "missing artist",
+			target:     "/lyrics?title=t",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "not acceptable",
+			target:     "/lyrics?artist=a&title=t",
+			accept:     "image/png",
+			wantStatus: http.StatusNotAcceptable,
+			wantCode:   problem.CodeNotAcceptable,
+		},
+		{
+			name:       "invalid lang",
+			target:     "/lyrics?artist=a&title=t&lang=nope!",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidLang,
+		},
+		{
+			name:       "artist's tracks only as json",
+			target:     "/lyrics?artist=a",
+			accept:     "text/plain",
+			wantStatus: http.StatusNotAcceptable,
+			wantCode:   problem.CodeNotAcceptable,
+		},
+		{
+			name:       "invalid limit",
+			target:     "/lyrics?artist=a&limit=0",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "invalid sort",
+			target:     "/lyrics?artist=a&sort=year",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "track not found",
+			target:     "/lyrics?artist=a&title=t",
+			err:        fmt.Errorf("service.track.Track: %w", trackService.ErrTrackNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeTrackNotFound,
+		},
+		{
+			name:       "artist's tracks not found",
+			target:     "/lyrics?artist=a",
+			err:        fmt.Errorf("service.track.ArtistTracks: %w", trackService.ErrArtistTracksNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeArtistTracksNotFound,
+		},
+		{
+			name:       "invalid cursor",
+			target:     "/lyrics?artist=a&cursor=nope",
+			err:        fmt.Errorf("service.track.ArtistTracks: %w", trackService.ErrInvalidCursor),
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidCursor,
+		},
+		{
+			name:       "unexpected error",
+			target:     "/lyrics?artist=a&title=t",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+		{
+			name:       "unexpected error for artist's tracks",
+			target:     "/lyrics?artist=a",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			called := func() {
+				if tt.err == nil {
+					t.Error("the service is called for an invalid request")
+				}
+			}
+
+			handler := get.New(slogdiscard.NewDiscardLogger(),
+				trackProviderFunc(func(context.Context, string, string) (*models.Track, error) {
+					called()
+					return nil, tt.err
+				}),
+				artistTracksProviderFunc(func(context.Context, string, models.PageQuery) (*models.TrackPage, error) {
+					called()
+					return nil, tt.err
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			if tt.accept != "" {
+				req.Header.Set("Accept", tt.accept)
+			}
+
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestGetArtistTracksPastLastPage(t *testing.T) {
+	handler := get.New(slogdiscard.NewDiscardLogger(), nil,
+		artistTracksProviderFunc(func(context.Context, string, models.PageQuery) (*models.TrackPage, error) {
+			return &models.TrackPage{}, nil
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+
+	handler(rec, httptest.NewRequest(http.MethodGet, "/lyrics?artist=Daft+Punk&cursor=abc", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got, want := strings.TrimSpace(rec.Body.String()), `{"tracks":[]}`; got != want {
+		t.Errorf("body = %s, want %s", got, want)
+	}
+}
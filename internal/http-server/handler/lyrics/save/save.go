@@ -3,86 +3,170 @@ package save
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log/slog"
 	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
 
 	"github.com/go-chi/render"
 	"github.com/go-playground/validator"
 
 	"lyrics-library/internal/domain/models"
-	resp "lyrics-library/internal/lib/api/response"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/etag"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/lang"
 	"lyrics-library/internal/lib/logger/sl"
-	trackService "lyrics-library/internal/service/track"
+	"lyrics-library/internal/lib/tracing"
 )
 
 type Request struct {
-	Artist string `json:"artist" validate:"required"`
-	Title  string `json:"title" validate:"required"`
+	Artist      string   `json:"artist" validate:"required"`
+	Title       string   `json:"title" validate:"required"`
+	TargetLangs []string `json:"target_langs" validate:"max=5"`
 }
 
 type TrackSaver interface {
-	Save(ctx context.Context, artist, title string) (*models.Track, error)
+	Save(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error)
+}
+
+type JobEnqueuer interface {
+	Enqueue(ctx context.Context, artist, title string, targetLangs []string) (*models.Job, error)
 }
 
 func New(
-	ctx context.Context,
 	log *slog.Logger,
 	trackSaver TrackSaver,
+	jobEnqueuer JobEnqueuer,
 ) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		const op = "handlers.song.save.New"
 
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
+
 		log := log.With(
 			slog.String("op", op),
 		)
 
-		log.Info("saving lyrics")
+		log.InfoContext(ctx, "saving lyrics")
 
 		var req Request
 
 		if err := render.DecodeJSON(r.Body, &req); err != nil {
-			log.Error("failed to decode request body", sl.Err(err))
+			log.ErrorContext(ctx, "failed to decode request body", sl.Err(err))
 
-			w.WriteHeader(http.StatusBadRequest)
-
-			render.JSON(w, r, resp.Error("invalid request"))
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "invalid request"))
 			return
 		}
 
-		log.Debug("request body decoded", slog.Any("request", req))
-
-		if err := validator.New().Struct(req); err != nil {
-			log.Error("invalid request", sl.Err(err))
+		log.DebugContext(ctx, "request body decoded", slog.Any("request", req))
 
-			w.WriteHeader(http.StatusBadRequest)
+		if err := validate.Struct(req); err != nil {
+			log.ErrorContext(ctx, "invalid request", sl.Err(err))
 
-			render.JSON(w, r, resp.Error("invalid request"))
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, problem.CodeValidationFailed,
+				validationDetail(err),
+			))
 			return
 		}
 
-		track, err := trackSaver.Save(ctx, req.Artist, req.Title)
+		targetLangs, err := normalizeLangs(req.TargetLangs)
 		if err != nil {
-			switch {
-			case errors.Is(err, trackService.ErrLyricsNotFound):
-				w.WriteHeader(http.StatusNotFound)
+			log.ErrorContext(ctx, "invalid target languages", sl.Err(err))
 
-				render.JSON(w, r, resp.Error("lyrics not found"))
-				return
-			case errors.Is(err, trackService.ErrFailedTranslateLyrics):
-				w.WriteHeader(http.StatusBadRequest)
-
-				render.JSON(w, r, resp.Error("failed translate lyrcis"))
-				return
-			default:
-				w.WriteHeader(http.StatusInternalServerError)
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, problem.CodeInvalidLang, err.Error()))
+			return
+		}
 
-				render.JSON(w, r, resp.Error("internal error"))
+		if async, _ := strconv.ParseBool(r.URL.Query().Get("async")); async {
+			job, err := jobEnqueuer.Enqueue(ctx, req.Artist, req.Title, targetLangs)
+			if err != nil {
+				apierror.Write(w, r, err)
 				return
 			}
+
+			w.Header().Set("Location", "/jobs/"+job.ID)
+			render.Status(r, http.StatusAccepted)
+
+			render.JSON(w, r, job)
+			return
+		}
+
+		track, created, err := trackSaver.Save(ctx, req.Artist, req.Title, targetLangs)
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
 		}
 
-		w.WriteHeader(http.StatusCreated)
+		etag.Set(w, track.Version, "")
+
+		if created {
+			render.Status(r, http.StatusCreated)
+		} else {
+			render.Status(r, http.StatusOK)
+		}
 
 		render.JSON(w, r, track)
 	}
 }
+
+var validate = newValidator()
+
+// newValidator names fields after their JSON keys in validation errors.
+func newValidator() *validator.Validate {
+	v := validator.New()
+
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+		return name
+	})
+
+	return v
+}
+
+func validationDetail(err error) string {
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return "invalid request"
+	}
+
+	details := make([]string, 0, len(fieldErrs))
+	for _, fieldErr := range fieldErrs {
+		switch fieldErr.Tag() {
+		case "required":
+			details = append(details, fieldErr.Field()+" is required")
+		case "max":
+			details = append(details, fmt.Sprintf("%s must have at most %s items", fieldErr.Field(), fieldErr.Param()))
+		default:
+			details = append(details, fieldErr.Field()+" is invalid")
+		}
+	}
+
+	return strings.Join(details, "; ")
+}
+
+func normalizeLangs(langs []string) ([]string, error) {
+	seen := make(map[string]struct{}, len(langs))
+	result := make([]string, 0, len(langs))
+
+	for _, code := range langs {
+		code = lang.Normalize(code)
+
+		if !lang.Valid(code) {
+			return nil, fmt.Errorf("invalid target language: %q", code)
+		}
+
+		if _, ok := seen[code]; ok {
+			continue
+		}
+
+		seen[code] = struct{}{}
+		result = append(result, code)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,156 @@
+package save_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/lyrics/save"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	trackService "lyrics-library/internal/service/track"
+)
+
+type trackSaverFunc func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error)
+
+func (f trackSaverFunc) Save(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+	return f(ctx, artist, title, targetLangs)
+}
+
+type jobEnqueuerFunc func(ctx context.Context, artist, title string, targetLangs []string) (*models.Job, error)
+
+func (f jobEnqueuerFunc) Enqueue(ctx context.Context, artist, title string, targetLangs []string) (*models.Job, error) {
+	return f(ctx, artist, title, targetLangs)
+}
+
+func TestSaveErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		async      bool
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+		wantRetry  string
+	}{
+		{
+			name:       "lyrics not found",
+			err:        fmt.Errorf("service.track.Save: %w", trackService.ErrLyricsNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeLyricsNotFound,
+		},
+		{
+			name:       "quota exceeded",
+			err:        fmt.Errorf("service.track.Save: %w", &trackService.QuotaExceededError{RetryAfter: time.Minute}),
+			wantStatus: http.StatusTooManyRequests,
+			wantCode:   problem.CodeQuotaExceeded,
+			wantRetry:  "60",
+		},
+		{
+			name:       "translation failed",
+			err:        fmt.Errorf("service.track.Save: %w", trackService.ErrFailedTranslateLyrics),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   problem.CodeTranslationFailed,
+		},
+		{
+			name:       "upstream unavailable",
+			err:        fmt.Errorf("service.track.Save: %w: %w", trackService.ErrUpstreamUnavailable, errors.New("timeout")),
+			wantStatus: http.StatusBadGateway,
+			wantCode:   problem.CodeUpstreamUnavailable,
+		},
+		{
+			name:       "unexpected error",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+		{
+			name:       "enqueue fails",
+			async:      true,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := save.New(slogdiscard.NewDiscardLogger(),
+				trackSaverFunc(func(context.Context, string, string, []string) (*models.Track, bool, error) {
+					return nil, false, tt.err
+				}),
+				jobEnqueuerFunc(func(context.Context, string, string, []string) (*models.Job, error) {
+					return nil, tt.err
+				}),
+			)
+
+			target := "/lyrics"
+			if tt.async {
+				target += "?async=true"
+			}
+
+			req := httptest.NewRequest(http.MethodPost, target, strings.NewReader(`{"artist":"a","title":"t"}`))
+			rec := httptest.NewRecorder()
+
+			handler(rec, req)
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+		})
+	}
+}
+
+func TestSaveValidation(t *testing.T) {
+	tests := []struct {
+		name       string
+		body       string
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "malformed body",
+			body:       `{"artist":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "missing title",
+			body:       `{"artist":"a"}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeValidationFailed,
+		},
+		{
+			name:       "invalid language",
+			body:       `{"artist":"a","title":"t","target_langs":["nope!"]}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidLang,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := save.New(slogdiscard.NewDiscardLogger(),
+				trackSaverFunc(func(context.Context, string, string, []string) (*models.Track, bool, error) {
+					t.Error("Save() is called for an invalid request")
+					return nil, false, nil
+				}),
+				nil,
+			)
+
+			rec := httptest.NewRecorder()
+
+			handler(rec, httptest.NewRequest(http.MethodPost, "/lyrics", strings.NewReader(tt.body)))
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
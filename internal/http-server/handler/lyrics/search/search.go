@@ -0,0 +1,85 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/pagination"
+	"lyrics-library/internal/lib/tracing"
+)
+
+type TrackSearcher interface {
+	Search(ctx context.Context, query models.SearchQuery) ([]*models.SearchHit, error)
+}
+
+type Response struct {
+	Results []*models.SearchHit `json:"results"`
+}
+
+func New(
+	log *slog.Logger,
+	trackSearcher TrackSearcher,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.song.search.New"
+
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
+
+		log := log.With(slog.String("op", op))
+
+		log.InfoContext(ctx, "searching lyrics")
+
+		query := r.URL.Query()
+
+		searchQuery := models.SearchQuery{
+			Query:  strings.TrimSpace(query.Get("q")),
+			Artist: query.Get("artist"),
+			Limit:  pagination.DefaultLimit,
+		}
+
+		if searchQuery.Query == "" {
+			log.ErrorContext(ctx, "missing 'q' parameter")
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "q is required"))
+			return
+		}
+
+		if limit := query.Get("limit"); limit != "" {
+			n, err := strconv.Atoi(limit)
+			if err != nil || n < 1 || n > pagination.MaxLimit {
+				log.ErrorContext(ctx, "invalid 'limit' parameter", slog.String("limit", limit))
+
+				problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest,
+					fmt.Sprintf("limit must be between 1 and %d", pagination.MaxLimit),
+				))
+				return
+			}
+
+			searchQuery.Limit = n
+		}
+
+		hits, err := trackSearcher.Search(ctx, searchQuery)
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+
+		if hits == nil {
+			hits = []*models.SearchHit{}
+		}
+
+		render.Status(r, http.StatusOK)
+
+		render.JSON(w, r, Response{Results: hits})
+	}
+}
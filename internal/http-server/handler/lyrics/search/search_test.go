@@ -0,0 +1,91 @@
+package search_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/lyrics/search"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+type trackSearcherFunc func(ctx context.Context, query models.SearchQuery) ([]*models.SearchHit, error)
+
+func (f trackSearcherFunc) Search(ctx context.Context, query models.SearchQuery) ([]*models.SearchHit, error) {
+	return f(ctx, query)
+}
+
+func TestSearchErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		target     string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "missing query",
+			target:     "/lyrics/search?q=%20",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "invalid limit",
+			target:     "/lyrics/search?q=love&limit=0",
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "unexpected error",
+			target:     "/lyrics/search?q=love",
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := search.New(slogdiscard.NewDiscardLogger(),
+				trackSearcherFunc(func(context.Context, models.SearchQuery) ([]*models.SearchHit, error) {
+					if tt.err == nil {
+						t.Error("Search() is called for an invalid request")
+					}
+
+					return nil, tt.err
+				}),
+			)
+
+			rec := httptest.NewRecorder()
+
+			handler(rec, httptest.NewRequest(http.MethodGet, tt.target, nil))
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestSearchNoResults(t *testing.T) {
+	handler := search.New(slogdiscard.NewDiscardLogger(),
+		trackSearcherFunc(func(context.Context, models.SearchQuery) ([]*models.SearchHit, error) {
+			return nil, nil
+		}),
+	)
+
+	rec := httptest.NewRecorder()
+
+	handler(rec, httptest.NewRequest(http.MethodGet, "/lyrics/search?q=love", nil))
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got := rec.Body.String(); got != "{\"results\":[]}\n" {
+		t.Errorf("body = %q, want an empty results array", got)
+	}
+}
@@ -0,0 +1,159 @@
+package update
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/etag"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/lang"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+)
+
+const maxBodySize = 1 << 20
+
+// Request is a partial update, omitted fields are left as they are.
+// Translations are keyed by language code.
+type Request struct {
+	Artist       *string                      `json:"artist"`
+	Title        *string                      `json:"title"`
+	Lyrics       []models.LineEdit            `json:"lyrics"`
+	Translations map[string][]models.LineEdit `json:"translations"`
+}
+
+type TrackUpdater interface {
+	Update(ctx context.Context, uuid string, version int64, update models.TrackUpdate) (*models.Track, error)
+}
+
+func New(
+	log *slog.Logger,
+	trackUpdater TrackUpdater,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.song.update.New"
+
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
+
+		log := log.With(slog.String("op", op))
+
+		log.InfoContext(ctx, "updating track")
+
+		id := chi.URLParam(r, "uuid")
+
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid uuid", sl.Err(err))
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidUUID, "invalid uuid"))
+			return
+		}
+
+		id = parsed.String()
+
+		version, err := etag.IfMatch(r)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid If-Match header", sl.Err(err))
+
+			if errors.Is(err, etag.ErrMissing) {
+				problem.Write(w, r, problem.New(http.StatusPreconditionRequired, problem.CodePreconditionRequired,
+					err.Error(),
+				))
+				return
+			}
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, err.Error()))
+			return
+		}
+
+		var req Request
+
+		if err := render.DecodeJSON(http.MaxBytesReader(w, r.Body, maxBodySize), &req); err != nil {
+			log.ErrorContext(ctx, "failed to decode request body", sl.Err(err))
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				problem.Write(w, r, problem.New(http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge,
+					"request body is too large",
+				))
+				return
+			}
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidRequest, "invalid request"))
+			return
+		}
+
+		translations, err := normalizeTranslations(req.Translations)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid translation language", sl.Err(err))
+
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, problem.CodeInvalidLang, err.Error()))
+			return
+		}
+
+		update := models.TrackUpdate{
+			Artist:       req.Artist,
+			Title:        req.Title,
+			Lyrics:       req.Lyrics,
+			Translations: translations,
+		}
+
+		if update.Artist == nil && update.Title == nil && len(update.Lyrics) == 0 && len(update.Translations) == 0 {
+			log.ErrorContext(ctx, "empty update")
+
+			problem.Write(w, r, problem.New(http.StatusUnprocessableEntity, problem.CodeValidationFailed,
+				"nothing to update",
+			))
+			return
+		}
+
+		track, err := trackUpdater.Update(ctx, id, version, update)
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+
+		etag.Set(w, track.Version, "")
+
+		render.Status(r, http.StatusOK)
+
+		render.JSON(w, r, track)
+	}
+}
+
+// normalizeTranslations normalizes the language codes and rejects codes
+// that normalize to the same language.
+func normalizeTranslations(
+	translations map[string][]models.LineEdit,
+) (map[string][]models.LineEdit, error) {
+	result := make(map[string][]models.LineEdit, len(translations))
+
+	for code, edits := range translations {
+		if len(edits) == 0 {
+			continue
+		}
+
+		normalized := lang.Normalize(code)
+		if !lang.Valid(normalized) {
+			return nil, fmt.Errorf("invalid translation language: %q", code)
+		}
+
+		if _, ok := result[normalized]; ok {
+			return nil, fmt.Errorf("duplicate translation language: %q", normalized)
+		}
+
+		result[normalized] = edits
+	}
+
+	return result, nil
+}
@@ -0,0 +1,211 @@
+package update_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/lyrics/update"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	trackService "lyrics-library/internal/service/track"
+)
+
+const trackUUID = "0b4e6a52-5a5c-4c43-9f0e-4f4f2d3a8c11"
+
+type trackUpdaterFunc func(ctx context.Context, uuid string, version int64, update models.TrackUpdate) (*models.Track, error)
+
+func (f trackUpdaterFunc) Update(ctx context.Context, uuid string, version int64, update models.TrackUpdate) (*models.Track, error) {
+	return f(ctx, uuid, version, update)
+}
+
+func TestUpdateErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		ifMatch    string
+		body       string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "invalid uuid",
+			id:         "nope",
+			ifMatch:    "*",
+			body:       `{"title":"t"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidUUID,
+		},
+		{
+			name:       "missing If-Match",
+			id:         trackUUID,
+			body:       `{"title":"t"}`,
+			wantStatus: http.StatusPreconditionRequired,
+			wantCode:   problem.CodePreconditionRequired,
+		},
+		{
+			name:       "invalid If-Match",
+			id:         trackUUID,
+			ifMatch:    "W/\"1\"",
+			body:       `{"title":"t"}`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "malformed body",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{"title":`,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidRequest,
+		},
+		{
+			name:       "invalid translation language",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{"translations":{"nope!":[{"line":0,"text":"x"}]}}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidLang,
+		},
+		{
+			name:       "nothing to update",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{}`,
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeValidationFailed,
+		},
+		{
+			name:       "track not found",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{"title":"t"}`,
+			err:        fmt.Errorf("service.track.Update: %w", trackService.ErrTrackNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeTrackNotFound,
+		},
+		{
+			name:       "track exists",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{"title":"t"}`,
+			err:        fmt.Errorf("service.track.Update: %w", trackService.ErrTrackExists),
+			wantStatus: http.StatusConflict,
+			wantCode:   problem.CodeTrackExists,
+		},
+		{
+			name:       "version mismatch",
+			id:         trackUUID,
+			ifMatch:    "\"3\"",
+			body:       `{"title":"t"}`,
+			err:        fmt.Errorf("service.track.Update: %w", trackService.ErrVersionMismatch),
+			wantStatus: http.StatusPreconditionFailed,
+			wantCode:   problem.CodeVersionMismatch,
+		},
+		{
+			name:       "invalid update",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{"title":"t"}`,
+			err:        fmt.Errorf("service.track.Update: %w", &trackService.InvalidUpdateError{Reason: "title must not be empty"}),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeValidationFailed,
+		},
+		{
+			name:       "unexpected error",
+			id:         trackUUID,
+			ifMatch:    "*",
+			body:       `{"title":"t"}`,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Patch("/lyrics/{uuid}", update.New(slogdiscard.NewDiscardLogger(),
+				trackUpdaterFunc(func(context.Context, string, int64, models.TrackUpdate) (*models.Track, error) {
+					if tt.err == nil {
+						t.Error("Update() is called for an invalid request")
+					}
+
+					return nil, tt.err
+				}),
+			))
+
+			req := httptest.NewRequest(http.MethodPatch, "/lyrics/"+tt.id, strings.NewReader(tt.body))
+			if tt.ifMatch != "" {
+				req.Header.Set("If-Match", tt.ifMatch)
+			}
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, req)
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestUpdateVersion(t *testing.T) {
+	router := chi.NewRouter()
+	router.Patch("/lyrics/{uuid}", update.New(slogdiscard.NewDiscardLogger(),
+		trackUpdaterFunc(func(ctx context.Context, uuid string, version int64, update models.TrackUpdate) (*models.Track, error) {
+			if version != 3 {
+				t.Errorf("Update() version = %d, want 3", version)
+			}
+
+			return &models.Track{UUID: uuid, Title: *update.Title, Version: version + 1}, nil
+		}),
+	))
+
+	req := httptest.NewRequest(http.MethodPatch, "/lyrics/"+trackUUID, strings.NewReader(`{"title":"t"}`))
+	req.Header.Set("If-Match", "\"3\"")
+
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+
+	if got := rec.Header().Get("ETag"); got != "\"4\"" {
+		t.Errorf("ETag = %s, want \"4\"", got)
+	}
+}
+
+func TestUpdateCanonicalUUID(t *testing.T) {
+	router := chi.NewRouter()
+	router.Patch("/lyrics/{uuid}", update.New(slogdiscard.NewDiscardLogger(),
+		trackUpdaterFunc(func(ctx context.Context, uuid string, version int64, update models.TrackUpdate) (*models.Track, error) {
+			if uuid != trackUUID {
+				t.Errorf("Update() uuid = %q, want %q", uuid, trackUUID)
+			}
+
+			return &models.Track{UUID: uuid, Version: 2}, nil
+		}),
+	))
+
+	req := httptest.NewRequest(http.MethodPatch, "/lyrics/urn:uuid:"+strings.ToUpper(trackUUID), strings.NewReader(`{"title":"t"}`))
+	req.Header.Set("If-Match", "*")
+
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,82 @@
+package uploadlrc
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/render"
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/etag"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/lrc"
+	"lyrics-library/internal/lib/tracing"
+)
+
+const maxFileSize = 1 << 20
+
+type SyncedLyricsSaver interface {
+	SaveSyncedLyrics(ctx context.Context, uuid string, lines []models.SyncedLine) (*models.Track, error)
+}
+
+func New(
+	log *slog.Logger,
+	saver SyncedLyricsSaver,
+) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		const op = "handlers.song.uploadlrc.New"
+
+		ctx, span := tracing.Start(r.Context(), op)
+		defer span.End()
+
+		log := log.With(slog.String("op", op))
+
+		log.InfoContext(ctx, "uploading lrc")
+
+		id := chi.URLParam(r, "uuid")
+
+		parsed, err := uuid.Parse(id)
+		if err != nil {
+			log.ErrorContext(ctx, "invalid uuid", sl.Err(err))
+
+			problem.Write(w, r, problem.New(http.StatusBadRequest, problem.CodeInvalidUUID, "invalid uuid"))
+			return
+		}
+
+		id = parsed.String()
+
+		lines, err := lrc.Parse(http.MaxBytesReader(w, r.Body, maxFileSize))
+		if err != nil {
+			log.ErrorContext(ctx, "invalid lrc", sl.Err(err))
+
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				problem.Write(w, r, problem.New(http.StatusRequestEntityTooLarge, problem.CodePayloadTooLarge,
+					"lrc file is too large",
+				))
+				return
+			}
+
+			apierror.Write(w, r, err)
+			return
+		}
+
+		track, err := saver.SaveSyncedLyrics(ctx, id, lines)
+		if err != nil {
+			apierror.Write(w, r, err)
+			return
+		}
+
+		etag.Set(w, track.Version, "")
+
+		render.Status(r, http.StatusOK)
+
+		render.JSON(w, r, track)
+	}
+}
@@ -0,0 +1,130 @@
+package uploadlrc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/handler/lyrics/uploadlrc"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	trackService "lyrics-library/internal/service/track"
+)
+
+const (
+	trackUUID = "0b4e6a52-5a5c-4c43-9f0e-4f4f2d3a8c11"
+	validLRC  = "[00:01.00]first\n[00:02.50]second\n"
+)
+
+type syncedLyricsSaverFunc func(ctx context.Context, uuid string, lines []models.SyncedLine) (*models.Track, error)
+
+func (f syncedLyricsSaverFunc) SaveSyncedLyrics(ctx context.Context, uuid string, lines []models.SyncedLine) (*models.Track, error) {
+	return f(ctx, uuid, lines)
+}
+
+func TestUploadLRCErrors(t *testing.T) {
+	tests := []struct {
+		name       string
+		id         string
+		body       string
+		err        error
+		wantStatus int
+		wantCode   problem.Code
+	}{
+		{
+			name:       "invalid uuid",
+			id:         "nope",
+			body:       validLRC,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   problem.CodeInvalidUUID,
+		},
+		{
+			name:       "invalid lrc",
+			id:         trackUUID,
+			body:       "[00:99.00]first\n",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidLRC,
+		},
+		{
+			name:       "empty lrc",
+			id:         trackUUID,
+			body:       "\n",
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   problem.CodeInvalidLRC,
+		},
+		{
+			name:       "file too large",
+			id:         trackUUID,
+			body:       strings.Repeat("[00:01.00]"+strings.Repeat("la ", 1000)+"\n", 400),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   problem.CodePayloadTooLarge,
+		},
+		{
+			name:       "track not found",
+			id:         trackUUID,
+			body:       validLRC,
+			err:        fmt.Errorf("service.track.SaveSyncedLyrics: %w", trackService.ErrTrackNotFound),
+			wantStatus: http.StatusNotFound,
+			wantCode:   problem.CodeTrackNotFound,
+		},
+		{
+			name:       "unexpected error",
+			id:         trackUUID,
+			body:       validLRC,
+			err:        errors.New("boom"),
+			wantStatus: http.StatusInternalServerError,
+			wantCode:   problem.CodeInternal,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := chi.NewRouter()
+			router.Put("/lyrics/{uuid}/lrc", uploadlrc.New(slogdiscard.NewDiscardLogger(),
+				syncedLyricsSaverFunc(func(context.Context, string, []models.SyncedLine) (*models.Track, error) {
+					if tt.err == nil {
+						t.Error("SaveSyncedLyrics() is called for an invalid request")
+					}
+
+					return nil, tt.err
+				}),
+			))
+
+			rec := httptest.NewRecorder()
+
+			router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/lyrics/"+tt.id+"/lrc", strings.NewReader(tt.body)))
+
+			problemtest.Check(t, rec.Result(), tt.wantStatus, tt.wantCode)
+		})
+	}
+}
+
+func TestUploadLRCCanonicalUUID(t *testing.T) {
+	router := chi.NewRouter()
+	router.Put("/lyrics/{uuid}/lrc", uploadlrc.New(slogdiscard.NewDiscardLogger(),
+		syncedLyricsSaverFunc(func(ctx context.Context, uuid string, lines []models.SyncedLine) (*models.Track, error) {
+			if uuid != trackUUID {
+				t.Errorf("SaveSyncedLyrics() uuid = %q, want %q", uuid, trackUUID)
+			}
+
+			return &models.Track{UUID: uuid, Synced: lines, Version: 2}, nil
+		}),
+	))
+
+	rec := httptest.NewRecorder()
+
+	router.ServeHTTP(rec, httptest.NewRequest(http.MethodPut,
+		"/lyrics/{"+strings.ToUpper(trackUUID)+"}/lrc", strings.NewReader(validLRC)))
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("status = %d, want %d", rec.Code, http.StatusOK)
+	}
+}
@@ -0,0 +1,394 @@
+package handler_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lyrics-library/api"
+)
+
+// spec checks responses against api/openapi.json. Only the parts of the
+// schema language the document uses are supported.
+type spec struct {
+	doc   map[string]any
+	paths map[string]*regexp.Regexp
+}
+
+var pathParam = regexp.MustCompile(`\\\{[^}]+\\\}`)
+
+func loadSpec(t *testing.T) *spec {
+	t.Helper()
+
+	var doc map[string]any
+	if err := json.Unmarshal(api.OpenAPI, &doc); err != nil {
+		t.Fatalf("failed to parse the OpenAPI document: %v", err)
+	}
+
+	s := &spec{doc: doc, paths: make(map[string]*regexp.Regexp)}
+
+	for path := range object(doc["paths"]) {
+		s.paths[path] = regexp.MustCompile("^" + pathParam.ReplaceAllString(regexp.QuoteMeta(path), `[^/]+`) + "$")
+	}
+
+	return s
+}
+
+// operations returns every documented operation as "METHOD /path".
+func (s *spec) operations() []string {
+	var ops []string
+
+	for path, item := range object(s.doc["paths"]) {
+		for method := range object(item) {
+			if method != "parameters" {
+				ops = append(ops, strings.ToUpper(method)+" "+path)
+			}
+		}
+	}
+
+	slices.Sort(ops)
+
+	return ops
+}
+
+// route finds the documented path of a request path. The most specific
+// path wins, so /lyrics/search is not taken for /lyrics/{uuid}.
+func (s *spec) route(path string) (string, bool) {
+	var (
+		best    string
+		literal = -1
+	)
+
+	for pattern, re := range s.paths {
+		if !re.MatchString(path) {
+			continue
+		}
+
+		if n := len(pathParam.ReplaceAllString(regexp.QuoteMeta(pattern), "")); n > literal {
+			best, literal = pattern, n
+		}
+	}
+
+	return best, literal >= 0
+}
+
+// check validates the status, content type and body of a response and
+// returns the operation it belongs to.
+func (s *spec) check(method, path string, status int, header http.Header, body []byte) (string, error) {
+	pattern, ok := s.route(path)
+	if !ok {
+		return "", fmt.Errorf("path %s is not documented", path)
+	}
+
+	operation := method + " " + pattern
+
+	op, ok := object(s.doc["paths"])[pattern].(map[string]any)[strings.ToLower(method)].(map[string]any)
+	if !ok {
+		return operation, errors.New("operation is not documented")
+	}
+
+	responses := object(op["responses"])
+
+	response, ok := responses[strconv.Itoa(status)].(map[string]any)
+	if !ok {
+		if response, ok = responses["default"].(map[string]any); !ok {
+			return operation, fmt.Errorf("status %d is not documented", status)
+		}
+	}
+
+	response = s.resolve(response)
+
+	content := object(response["content"])
+	if len(content) == 0 {
+		if len(body) != 0 {
+			return operation, fmt.Errorf("status %d is documented without a body, got %q", status, body)
+		}
+
+		return operation, nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(header.Get("Content-Type"))
+	if err != nil {
+		return operation, fmt.Errorf("invalid Content-Type %q: %w", header.Get("Content-Type"), err)
+	}
+
+	media, ok := content[mediaType].(map[string]any)
+	if !ok {
+		return operation, fmt.Errorf("Content-Type %s is not documented for status %d, want one of %v",
+			mediaType, status, slices.Sorted(maps.Keys(content)))
+	}
+
+	schema, _ := media["schema"].(map[string]any)
+
+	if mediaType != "application/json" && !strings.HasSuffix(mediaType, "+json") {
+		return operation, s.validate(schema, string(body), "body")
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return operation, fmt.Errorf("invalid json body: %w", err)
+	}
+
+	return operation, s.validate(schema, value, "body")
+}
+
+func (s *spec) resolve(node map[string]any) map[string]any {
+	for {
+		ref, ok := node["$ref"].(string)
+		if !ok {
+			return node
+		}
+
+		var target any = s.doc
+		for _, key := range strings.Split(strings.TrimPrefix(ref, "#/"), "/") {
+			target = object(target)[key]
+		}
+
+		node = object(target)
+	}
+}
+
+func (s *spec) validate(schema map[string]any, value any, at string) error {
+	if schema == nil {
+		return nil
+	}
+
+	schema = s.resolve(schema)
+
+	if value == nil {
+		if nullable, _ := schema["nullable"].(bool); nullable {
+			return nil
+		}
+
+		return fmt.Errorf("%s: null is not allowed", at)
+	}
+
+	if oneOf, ok := schema["oneOf"].([]any); ok {
+		matched := 0
+		for _, option := range oneOf {
+			if s.validate(object(option), value, at) == nil {
+				matched++
+			}
+		}
+
+		if matched != 1 {
+			return fmt.Errorf("%s: matches %d schemas of oneOf, want 1", at, matched)
+		}
+
+		return nil
+	}
+
+	if enum, ok := schema["enum"].([]any); ok {
+		if !slices.ContainsFunc(enum, func(v any) bool { return fmt.Sprint(v) == fmt.Sprint(value) }) {
+			return fmt.Errorf("%s: %v is not one of %v", at, value, enum)
+		}
+	}
+
+	switch schema["type"] {
+	case "object":
+		return s.validateObject(schema, value, at)
+	case "array":
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("%s: %T is not an array", at, value)
+		}
+
+		var errs []error
+		for i, item := range items {
+			errs = append(errs, s.validate(object(schema["items"]), item, fmt.Sprintf("%s[%d]", at, i)))
+		}
+
+		return errors.Join(errs...)
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: %T is not a string", at, value)
+		}
+
+		switch schema["format"] {
+		case "uuid":
+			if err := uuid.Validate(str); err != nil {
+				return fmt.Errorf("%s: %q is not a uuid", at, str)
+			}
+		case "date-time":
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s: %q is not a date-time", at, str)
+			}
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if !ok {
+			return fmt.Errorf("%s: %T is not an integer", at, value)
+		}
+
+		if _, err := n.Int64(); err != nil {
+			return fmt.Errorf("%s: %s is not an integer", at, n)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s: %T is not a number", at, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: %T is not a boolean", at, value)
+		}
+	}
+
+	return nil
+}
+
+// validateObject also rejects properties the document doesn't mention,
+// an undocumented field is what the document is there to prevent. An
+// object without properties is free-form.
+func (s *spec) validateObject(schema map[string]any, value any, at string) error {
+	obj, ok := value.(map[string]any)
+	if !ok {
+		return fmt.Errorf("%s: %T is not an object", at, value)
+	}
+
+	var errs []error
+
+	required, _ := schema["required"].([]any)
+
+	for _, name := range required {
+		if _, ok := obj[name.(string)]; !ok {
+			errs = append(errs, fmt.Errorf("%s: required property %q is missing", at, name))
+		}
+	}
+
+	properties := object(schema["properties"])
+	additional, _ := schema["additionalProperties"].(map[string]any)
+
+	for name, v := range obj {
+		switch property, ok := properties[name].(map[string]any); {
+		case ok:
+			errs = append(errs, s.validate(property, v, at+"."+name))
+		case additional != nil:
+			errs = append(errs, s.validate(additional, v, at+"."+name))
+		case len(properties) == 0:
+		default:
+			errs = append(errs, fmt.Errorf("%s: property %q is not documented", at, name))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func object(v any) map[string]any {
+	m, _ := v.(map[string]any)
+
+	return m
+}
+
+// specTransport checks every response the test client gets against the
+// OpenAPI document and records the operations that have been checked.
+type specTransport struct {
+	t    *testing.T
+	spec *spec
+	base http.RoundTripper
+	seen map[string]bool
+}
+
+func (tr *specTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := tr.base.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+
+	if err != nil {
+		return nil, err
+	}
+
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+
+	operation, err := tr.spec.check(req.Method, req.URL.Path, resp.StatusCode, resp.Header, body)
+	if err != nil {
+		tr.t.Errorf("%s %s: %d response doesn't match the OpenAPI document: %v",
+			req.Method, req.URL.Path, resp.StatusCode, err)
+	} else {
+		tr.seen[operation] = true
+	}
+
+	return resp, nil
+}
+
+// TestOpenAPI calls every documented operation, the responses are checked
+// by specTransport.
+func TestOpenAPI(t *testing.T) {
+	srv := newServer(t)
+
+	saved := saveTrack(t, srv, "Daft Punk", "Aerodynamic")
+
+	requests := []struct {
+		method string
+		path   string
+		body   string
+		header http.Header
+	}{
+		{method: http.MethodPost, path: "/lyrics?async=true", body: `{"artist":"Justice","title":"Genesis","target_langs":["fr"]}`},
+		{method: http.MethodGet, path: "/lyrics?artist=daft+punk&title=aerodynamic"},
+		{method: http.MethodGet, path: "/lyrics?artist=daft+punk&limit=1"},
+		{method: http.MethodGet, path: "/lyrics?artist=daft+punk&title=aerodynamic", header: http.Header{"Accept": {"text/csv"}}},
+		{method: http.MethodGet, path: "/lyrics?artist=daft+punk&title=aerodynamic&lang=de", header: http.Header{"Accept": {"text/markdown"}}},
+		{method: http.MethodGet, path: "/lyrics?artist=nobody"},
+		{method: http.MethodGet, path: "/lyrics?artist=daft+punk", header: http.Header{"Accept": {"image/png"}}},
+		{method: http.MethodGet, path: "/lyrics/search?q=aerodynamic"},
+		{method: http.MethodGet, path: "/lyrics/search?q=aerodynamic&limit=1000"},
+		{method: http.MethodGet, path: "/lyrics/" + saved.UUID + "?lang=de"},
+		{method: http.MethodGet, path: "/lyrics/" + saved.UUID + ".txt"},
+		{method: http.MethodGet, path: "/lyrics/nope"},
+		{method: http.MethodPatch, path: "/lyrics/" + saved.UUID, body: `{"title":"Aerodynamite"}`},
+		{method: http.MethodPatch, path: "/lyrics/" + saved.UUID, body: `{"title":"Aerodynamite"}`, header: http.Header{"If-Match": {`"1"`}}},
+		{method: http.MethodPatch, path: "/lyrics/" + saved.UUID, body: `{"translations":{"de":[{"line":1,"text":"erste"}]}}`, header: http.Header{"If-Match": {`"2"`}}},
+		{method: http.MethodPatch, path: "/lyrics/" + saved.UUID, body: `{"title":"Aerodynamite"}`, header: http.Header{"If-Match": {`"1"`}}},
+		{method: http.MethodGet, path: "/lyrics/" + saved.UUID + ".lrc"},
+		{method: http.MethodPut, path: "/lyrics/" + saved.UUID + "/lrc", body: "[ar:Daft Punk]\n[00:01.00]first\n"},
+		{method: http.MethodPut, path: "/lyrics/" + saved.UUID + "/lrc", body: "first\n"},
+		{method: http.MethodGet, path: "/lyrics/" + saved.UUID + ".lrc"},
+		{method: http.MethodDelete, path: "/lyrics/" + saved.UUID},
+		{method: http.MethodDelete, path: "/lyrics/" + saved.UUID},
+		{method: http.MethodGet, path: "/jobs/nope"},
+		{method: http.MethodGet, path: "/openapi.json"},
+		{method: http.MethodGet, path: "/docs"},
+		{method: http.MethodGet, path: "/metrics"},
+		{method: http.MethodGet, path: "/healthz"},
+		{method: http.MethodGet, path: "/readyz"},
+	}
+
+	for _, req := range requests {
+		resp := do(t, srv, req.method, req.path, req.body, req.header)
+
+		if req.method == http.MethodPost && resp.StatusCode == http.StatusAccepted {
+			job := decode[struct{ ID string }](t, resp)
+
+			do(t, srv, http.MethodGet, "/jobs/"+job.ID, "", nil)
+		}
+	}
+
+	seen := srv.Client().Transport.(*specTransport).seen
+
+	for _, operation := range loadSpec(t).operations() {
+		if !seen[operation] {
+			t.Errorf("%s is not covered", operation)
+		}
+	}
+}
@@ -0,0 +1,93 @@
+package accesslog_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/trace"
+
+	"lyrics-library/internal/http-server/middleware/accesslog"
+	"lyrics-library/internal/http-server/middleware/requestid"
+	"lyrics-library/internal/lib/logger/slogctx"
+)
+
+var spanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: trace.FlagsSampled,
+})
+
+// withSpan stands for tracing.Handler, which starts the server span.
+func withSpan(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(w, r.WithContext(trace.ContextWithSpanContext(r.Context(), spanContext)))
+	})
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus float64
+		wantLevel  string
+		wantRoute  string
+	}{
+		{name: "success", path: "/lyrics/42", wantStatus: http.StatusOK, wantLevel: "INFO", wantRoute: "/lyrics/{uuid}"},
+		{name: "server error", path: "/fail", wantStatus: http.StatusInternalServerError, wantLevel: "ERROR", wantRoute: "/fail"},
+		{name: "no route", path: "/nope", wantStatus: http.StatusNotFound, wantLevel: "INFO", wantRoute: ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			log := slog.New(slogctx.NewHandler(slog.NewJSONHandler(&buf, nil)))
+
+			router := chi.NewRouter()
+			router.Use(requestid.New())
+			router.Use(accesslog.New(log))
+			router.Get("/lyrics/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			})
+			router.Get("/fail", func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(http.StatusInternalServerError)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set(requestid.Header, "req-1")
+
+			withSpan(router).ServeHTTP(httptest.NewRecorder(), req)
+
+			var line map[string]any
+			if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+				t.Fatalf("log line %q: %v", buf.String(), err)
+			}
+
+			want := map[string]any{
+				"msg":        "request completed",
+				"level":      tt.wantLevel,
+				"method":     http.MethodGet,
+				"path":       tt.path,
+				"route":      tt.wantRoute,
+				"status":     tt.wantStatus,
+				"request_id": "req-1",
+				"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+			}
+
+			for key, value := range want {
+				if line[key] != value {
+					t.Errorf("%s = %v, want %v", key, line[key], value)
+				}
+			}
+
+			if _, ok := line["duration"]; !ok {
+				t.Error("duration is missing")
+			}
+		})
+	}
+}
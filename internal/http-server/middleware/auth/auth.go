@@ -0,0 +1,110 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/http-server/apierror"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/sl"
+	authService "lyrics-library/internal/service/auth"
+)
+
+const headerAPIKey = "X-API-Key"
+
+type Authenticator interface {
+	Authenticate(ctx context.Context, key string) (*models.APIKey, error)
+}
+
+type ctxKey struct{}
+
+// KeyFromContext returns the API key the request has been authenticated
+// with.
+func KeyFromContext(ctx context.Context) (*models.APIKey, bool) {
+	key, ok := ctx.Value(ctxKey{}).(*models.APIKey)
+
+	return key, ok
+}
+
+// New authenticates requests by the key from the X-API-Key header or an
+// "Authorization: Bearer" header and rejects the ones without a valid key.
+// The key becomes the client id of the request.
+func New(
+	log *slog.Logger,
+	authenticator Authenticator,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := requestKey(r)
+			if key == "" {
+				unauthorized(w, r, "api key is required")
+				return
+			}
+
+			apiKey, err := authenticator.Authenticate(r.Context(), key)
+			if err != nil {
+				if errors.Is(err, authService.ErrInvalidKey) {
+					unauthorized(w, r, "invalid api key")
+					return
+				}
+
+				log.ErrorContext(r.Context(), "failed to authenticate request", sl.Err(err))
+
+				apierror.Write(w, r, err)
+				return
+			}
+
+			ctx := context.WithValue(r.Context(), ctxKey{}, apiKey)
+			ctx = clientid.WithContext(ctx, "key:"+apiKey.ID)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// RequireScope rejects requests whose key doesn't grant scope. It must run
+// after New.
+func RequireScope(scope models.Scope) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			apiKey, ok := KeyFromContext(r.Context())
+			if !ok {
+				unauthorized(w, r, "api key is required")
+				return
+			}
+
+			if !apiKey.HasScope(scope) {
+				problem.Write(w, r, problem.New(http.StatusForbidden, problem.CodeForbidden,
+					"api key lacks the "+string(scope)+" scope",
+				))
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+func requestKey(r *http.Request) string {
+	if key := r.Header.Get(headerAPIKey); key != "" {
+		return key
+	}
+
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if ok && strings.EqualFold(scheme, "Bearer") {
+		return strings.TrimSpace(token)
+	}
+
+	return ""
+}
+
+func unauthorized(w http.ResponseWriter, r *http.Request, msg string) {
+	w.Header().Set("WWW-Authenticate", `Bearer realm="lyrics-library"`)
+
+	problem.Write(w, r, problem.New(http.StatusUnauthorized, problem.CodeUnauthorized, msg))
+}
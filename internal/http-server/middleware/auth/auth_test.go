@@ -0,0 +1,93 @@
+package auth_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	mwAuth "lyrics-library/internal/http-server/middleware/auth"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	authService "lyrics-library/internal/service/auth"
+)
+
+type authenticatorFunc func(ctx context.Context, key string) (*models.APIKey, error)
+
+func (f authenticatorFunc) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	return f(ctx, key)
+}
+
+func TestNew(t *testing.T) {
+	authenticator := authenticatorFunc(func(ctx context.Context, key string) (*models.APIKey, error) {
+		if key != "secret" {
+			return nil, authService.ErrInvalidKey
+		}
+
+		return &models.APIKey{ID: "k1", Scopes: []models.Scope{models.ScopeRead}}, nil
+	})
+
+	tests := []struct {
+		name         string
+		header       string
+		value        string
+		wantStatus   int
+		wantClientID string
+	}{
+		{
+			name:         "api key header",
+			header:       "X-API-Key",
+			value:        "secret",
+			wantStatus:   http.StatusOK,
+			wantClientID: "key:k1",
+		},
+		{
+			name:         "bearer token",
+			header:       "Authorization",
+			value:        "Bearer secret",
+			wantStatus:   http.StatusOK,
+			wantClientID: "key:k1",
+		},
+		{
+			name:       "invalid key",
+			header:     "X-API-Key",
+			value:      "wrong",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "no key",
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotClientID string
+
+			handler := mwAuth.New(slogdiscard.NewDiscardLogger(), authenticator)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					gotClientID = clientid.FromContext(r.Context())
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/lyrics", nil)
+			req = req.WithContext(clientid.WithContext(req.Context(), "ip:203.0.113.7"))
+			if tt.header != "" {
+				req.Header.Set(tt.header, tt.value)
+			}
+
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if gotClientID != tt.wantClientID {
+				t.Errorf("client id = %q, want %q", gotClientID, tt.wantClientID)
+			}
+		})
+	}
+}
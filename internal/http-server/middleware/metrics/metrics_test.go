@@ -0,0 +1,60 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+
+	mwMetrics "lyrics-library/internal/http-server/middleware/metrics"
+	"lyrics-library/internal/lib/metrics"
+)
+
+func TestNew(t *testing.T) {
+	m := metrics.New()
+
+	router := chi.NewRouter()
+	router.Use(mwMetrics.New(m))
+	router.Get("/lyrics/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("ok"))
+	})
+	router.Delete("/lyrics/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	requests := []struct{ method, path string }{
+		{http.MethodGet, "/lyrics/1"},
+		{http.MethodGet, "/lyrics/2"},
+		{http.MethodDelete, "/lyrics/3"},
+		{http.MethodGet, "/nope"},
+	}
+
+	for _, req := range requests {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(req.method, req.path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+
+	want := []string{
+		`lyrics_library_http_requests_total{method="GET",route="/lyrics/{uuid}",status="200"} 2`,
+		`lyrics_library_http_requests_total{method="DELETE",route="/lyrics/{uuid}",status="204"} 1`,
+		`lyrics_library_http_requests_total{method="GET",route="unmatched",status="404"} 1`,
+		`lyrics_library_http_request_duration_seconds_count{method="GET",route="/lyrics/{uuid}",status="200"} 2`,
+	}
+
+	for _, line := range want {
+		if !strings.Contains(string(body), line) {
+			t.Errorf("metrics don't contain %s", line)
+		}
+	}
+
+	if strings.Contains(string(body), `route="/lyrics/1"`) {
+		t.Error("metrics are labeled with the raw path")
+	}
+}
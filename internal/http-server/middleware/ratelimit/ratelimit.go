@@ -0,0 +1,52 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/sl"
+)
+
+type Limiter interface {
+	AllowRequest(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+}
+
+// New limits every client with a token bucket refilled at rate tokens per
+// second up to burst. Clients are told apart by the client id from the
+// request context, set by the realip middleware and replaced with the API
+// key by auth, so a limiter mounted before auth limits by IP address.
+//
+// Requests are let through when the limiter fails, so an outage of the
+// shared store doesn't take the API down.
+func New(
+	log *slog.Logger,
+	limiter Limiter,
+	rate float64,
+	burst int,
+) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := r.Context()
+			id := clientid.FromContext(ctx)
+
+			allowed, retryAfter, err := limiter.AllowRequest(ctx, id, rate, burst)
+			if err != nil {
+				log.ErrorContext(ctx, "failed to check rate limit", slog.String("client", id), sl.Err(err))
+			} else if !allowed {
+				log.WarnContext(ctx, "rate limit exceeded", slog.String("client", id))
+
+				p := problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, "rate limit exceeded")
+				p.RetryAfter = retryAfter
+
+				problem.Write(w, r, p)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,86 @@
+package ratelimit_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/http-server/middleware/ratelimit"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+type limiterFunc func(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+
+func (f limiterFunc) AllowRequest(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+	return f(ctx, key, rate, burst)
+}
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name       string
+		allowed    bool
+		retryAfter time.Duration
+		err        error
+		wantStatus int
+		wantRetry  string
+	}{
+		{
+			name:       "allowed",
+			allowed:    true,
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "limited",
+			retryAfter: 1500 * time.Millisecond,
+			wantStatus: http.StatusTooManyRequests,
+			wantRetry:  "2",
+		},
+		{
+			name:       "limiter fails open",
+			err:        errors.New("redis is down"),
+			wantStatus: http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotKey string
+			var gotRate float64
+			var gotBurst int
+
+			limiter := limiterFunc(func(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error) {
+				gotKey, gotRate, gotBurst = key, rate, burst
+
+				return tt.allowed, tt.retryAfter, tt.err
+			})
+
+			handler := ratelimit.New(slogdiscard.NewDiscardLogger(), limiter, 5, 20)(
+				http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+					w.WriteHeader(http.StatusOK)
+				}),
+			)
+
+			req := httptest.NewRequest(http.MethodGet, "/lyrics", nil)
+			req = req.WithContext(clientid.WithContext(req.Context(), "key:k1"))
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if got := rec.Header().Get("Retry-After"); got != tt.wantRetry {
+				t.Errorf("Retry-After = %q, want %q", got, tt.wantRetry)
+			}
+
+			if gotKey != "key:k1" || gotRate != 5 || gotBurst != 20 {
+				t.Errorf("AllowRequest(%q, %v, %d), want (%q, 5, 20)", gotKey, gotRate, gotBurst, "key:k1")
+			}
+		})
+	}
+}
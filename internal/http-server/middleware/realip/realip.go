@@ -0,0 +1,116 @@
+package realip
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+
+	"lyrics-library/internal/lib/clientid"
+)
+
+const headerForwardedFor = "X-Forwarded-For"
+
+// ParsePrefixes parses proxy addresses given as CIDRs or single IPs.
+func ParsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+
+		if strings.Contains(value, "/") {
+			prefix, err := netip.ParsePrefix(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid proxy %q: %w", value, err)
+			}
+
+			prefixes = append(prefixes, prefix.Masked())
+
+			continue
+		}
+
+		addr, err := netip.ParseAddr(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy %q: %w", value, err)
+		}
+
+		addr = addr.Unmap()
+
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+
+	return prefixes, nil
+}
+
+// New sets r.RemoteAddr to the address of the client and stores it as the
+// client id ("ip:<addr>"), which the auth middleware replaces with the API
+// key. X-Forwarded-For is only read when the request comes from one of the
+// trusted proxies: the client is the right-most address that isn't a
+// trusted proxy, so entries a client adds itself are ignored.
+func New(trusted []netip.Prefix) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			addr := clientAddr(r, trusted)
+
+			r.RemoteAddr = addr
+			ctx := clientid.WithContext(r.Context(), "ip:"+addr)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+func clientAddr(r *http.Request, trusted []netip.Prefix) string {
+	peer := host(r.RemoteAddr)
+
+	addr, err := netip.ParseAddr(peer)
+	if err != nil || !isTrusted(addr, trusted) {
+		return peer
+	}
+
+	var hops []string
+	for _, header := range r.Header.Values(headerForwardedFor) {
+		hops = append(hops, strings.Split(header, ",")...)
+	}
+
+	for i := len(hops) - 1; i >= 0; i-- {
+		hop, err := netip.ParseAddr(strings.TrimSpace(hops[i]))
+		if err != nil {
+			// Anything left of a malformed entry may be forged.
+			return addr.String()
+		}
+
+		addr = hop.Unmap()
+
+		if !isTrusted(addr, trusted) {
+			break
+		}
+	}
+
+	return addr.String()
+}
+
+func isTrusted(addr netip.Addr, trusted []netip.Prefix) bool {
+	addr = addr.Unmap()
+
+	for _, prefix := range trusted {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func host(remoteAddr string) string {
+	h, _, err := net.SplitHostPort(remoteAddr)
+	if err != nil {
+		return remoteAddr
+	}
+
+	return h
+}
@@ -0,0 +1,122 @@
+package realip_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lyrics-library/internal/http-server/middleware/realip"
+	"lyrics-library/internal/lib/clientid"
+)
+
+func TestNew(t *testing.T) {
+	trusted, err := realip.ParsePrefixes([]string{"10.0.0.0/8", "192.168.1.1"})
+	if err != nil {
+		t.Fatalf("ParsePrefixes() error = %v", err)
+	}
+
+	tests := []struct {
+		name         string
+		remoteAddr   string
+		forwardedFor []string
+		wantAddr     string
+		wantClientID string
+	}{
+		{
+			name:         "direct client",
+			remoteAddr:   "203.0.113.7:5000",
+			wantAddr:     "203.0.113.7",
+			wantClientID: "ip:203.0.113.7",
+		},
+		{
+			name:         "untrusted peer can't forge the header",
+			remoteAddr:   "203.0.113.7:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			wantAddr:     "203.0.113.7",
+			wantClientID: "ip:203.0.113.7",
+		},
+		{
+			name:         "trusted proxy",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"198.51.100.1"},
+			wantAddr:     "198.51.100.1",
+			wantClientID: "ip:198.51.100.1",
+		},
+		{
+			name:         "chain of trusted proxies",
+			remoteAddr:   "192.168.1.1:5000",
+			forwardedFor: []string{"198.51.100.1, 10.0.0.5", "10.0.0.6"},
+			wantAddr:     "198.51.100.1",
+			wantClientID: "ip:198.51.100.1",
+		},
+		{
+			name:         "entries left of the client are ignored",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"1.1.1.1, 198.51.100.1"},
+			wantAddr:     "198.51.100.1",
+			wantClientID: "ip:198.51.100.1",
+		},
+		{
+			name:         "malformed entry",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"1.1.1.1, bogus, 10.0.0.5"},
+			wantAddr:     "10.0.0.5",
+			wantClientID: "ip:10.0.0.5",
+		},
+		{
+			name:         "trusted proxy without the header",
+			remoteAddr:   "10.1.2.3:5000",
+			wantAddr:     "10.1.2.3",
+			wantClientID: "ip:10.1.2.3",
+		},
+		{
+			name:         "ipv6 client",
+			remoteAddr:   "10.1.2.3:5000",
+			forwardedFor: []string{"2001:db8::1"},
+			wantAddr:     "2001:db8::1",
+			wantClientID: "ip:2001:db8::1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotAddr, gotClientID string
+
+			handler := realip.New(trusted)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				gotAddr = r.RemoteAddr
+				gotClientID = clientid.FromContext(r.Context())
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.RemoteAddr = tt.remoteAddr
+			for _, value := range tt.forwardedFor {
+				req.Header.Add("X-Forwarded-For", value)
+			}
+
+			handler.ServeHTTP(httptest.NewRecorder(), req)
+
+			if gotAddr != tt.wantAddr {
+				t.Errorf("RemoteAddr = %q, want %q", gotAddr, tt.wantAddr)
+			}
+
+			if gotClientID != tt.wantClientID {
+				t.Errorf("client id = %q, want %q", gotClientID, tt.wantClientID)
+			}
+		})
+	}
+}
+
+func TestParsePrefixes(t *testing.T) {
+	prefixes, err := realip.ParsePrefixes([]string{" 10.0.0.0/8", "", "::1"})
+	if err != nil {
+		t.Fatalf("ParsePrefixes() error = %v", err)
+	}
+
+	if len(prefixes) != 2 || prefixes[0].String() != "10.0.0.0/8" || prefixes[1].String() != "::1/128" {
+		t.Errorf("ParsePrefixes() = %v", prefixes)
+	}
+
+	if _, err := realip.ParsePrefixes([]string{"proxy.local"}); err == nil {
+		t.Error("ParsePrefixes() error = nil, want an error for a host name")
+	}
+}
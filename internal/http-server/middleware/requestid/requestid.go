@@ -0,0 +1,51 @@
+package requestid
+
+import (
+	"net/http"
+
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/lib/logger/slogctx"
+)
+
+const (
+	Header = "X-Request-ID"
+
+	maxLength = 128
+)
+
+// New takes the request id from the X-Request-ID header or generates one,
+// stores it in the request context for logging and echoes it in the
+// response.
+func New() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			id := r.Header.Get(Header)
+			if !valid(id) {
+				id = uuid.NewString()
+			}
+
+			w.Header().Set(Header, id)
+
+			ctx := slogctx.WithRequestID(r.Context(), id)
+
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// valid accepts short ids of printable ASCII, so a client can't forge log
+// lines.
+func valid(id string) bool {
+	if id == "" || len(id) > maxLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,67 @@
+package requestid_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/http-server/middleware/requestid"
+	"lyrics-library/internal/lib/logger/slogctx"
+)
+
+func TestNew(t *testing.T) {
+	tests := []struct {
+		name     string
+		incoming string
+		keep     bool
+	}{
+		{name: "missing", incoming: ""},
+		{name: "valid", incoming: "req-42_abc.DEF", keep: true},
+		{name: "longest accepted", incoming: strings.Repeat("a", 128), keep: true},
+		{name: "too long", incoming: strings.Repeat("a", 129)},
+		{name: "space", incoming: "req 42"},
+		{name: "line break", incoming: "req\n42"},
+		{name: "non-ASCII", incoming: "запрос"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got string
+
+			handler := requestid.New()(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				id, ok := slogctx.RequestID(r.Context())
+				if !ok {
+					t.Error("request id is missing from the context")
+				}
+
+				got = id
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/lyrics", nil)
+			if tt.incoming != "" {
+				req.Header.Set(requestid.Header, tt.incoming)
+			}
+
+			rec := httptest.NewRecorder()
+
+			handler.ServeHTTP(rec, req)
+
+			if tt.keep && got != tt.incoming {
+				t.Errorf("request id = %q, want %q", got, tt.incoming)
+			}
+
+			if !tt.keep {
+				if err := uuid.Validate(got); err != nil {
+					t.Errorf("request id = %q, want a generated UUID", got)
+				}
+			}
+
+			if echoed := rec.Header().Get(requestid.Header); echoed != got {
+				t.Errorf("%s = %q, want %q", requestid.Header, echoed, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package tracing
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// New names the server span after the matched chi route pattern. The
+// pattern is only known once routing is done, so the span opened by
+// tracing.Handler is renamed after the request has been served.
+func New() func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(w, r)
+
+			rctx := chi.RouteContext(r.Context())
+			if rctx == nil {
+				return
+			}
+
+			pattern := rctx.RoutePattern()
+			if pattern == "" {
+				return
+			}
+
+			span := trace.SpanFromContext(r.Context())
+			span.SetName(r.Method + " " + pattern)
+			span.SetAttributes(attribute.String("http.route", pattern))
+		})
+	}
+}
@@ -0,0 +1,59 @@
+package tracing_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	mwTracing "lyrics-library/internal/http-server/middleware/tracing"
+	"lyrics-library/internal/lib/tracing"
+)
+
+func TestNew(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	router := chi.NewRouter()
+	router.Use(mwTracing.New())
+	router.Get("/lyrics/{uuid}", func(w http.ResponseWriter, r *http.Request) {
+		_, span := tracing.Start(r.Context(), "handlers.lyrics.byuuid.New")
+		span.End()
+	})
+
+	tracing.Handler(router).ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/lyrics/42", nil))
+
+	spans := recorder.Ended()
+	if len(spans) != 2 {
+		t.Fatalf("spans = %d, want 2", len(spans))
+	}
+
+	handler, server := spans[0], spans[1]
+
+	if got, want := server.Name(), "GET /lyrics/{uuid}"; got != want {
+		t.Errorf("server span name = %q, want %q", got, want)
+	}
+
+	if !hasAttribute(server.Attributes(), attribute.String("http.route", "/lyrics/{uuid}")) {
+		t.Errorf("server span attributes = %v, want http.route", server.Attributes())
+	}
+
+	if handler.Parent().SpanID() != server.SpanContext().SpanID() {
+		t.Error("handler span isn't a child of the server span")
+	}
+}
+
+func hasAttribute(attrs []attribute.KeyValue, want attribute.KeyValue) bool {
+	for _, attr := range attrs {
+		if attr == want {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package etag
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+var (
+	ErrMissing = errors.New("If-Match header is required")
+	ErrInvalid = errors.New("If-Match must be a single entity tag or *")
+)
+
+// Format returns the entity tag of a track version. Representations other
+// than the full JSON track, another format or a single translation, are
+// named by variant, so that every representation has its own tag.
+func Format(version int64, variant string) string {
+	tag := strconv.FormatInt(version, 10)
+	if variant != "" {
+		tag += "-" + variant
+	}
+
+	return `"` + tag + `"`
+}
+
+// Set sends the entity tag of a representation of version in the ETag
+// header.
+func Set(w http.ResponseWriter, version int64, variant string) {
+	w.Header().Set("ETag", Format(version, variant))
+}
+
+// IfMatch returns the track version the If-Match header of r requires, the
+// tag of any representation of the version is accepted. "*" matches any
+// version and is returned as 0.
+func IfMatch(r *http.Request) (int64, error) {
+	header := strings.TrimSpace(r.Header.Get("If-Match"))
+
+	switch header {
+	case "":
+		return 0, ErrMissing
+	case "*":
+		return 0, nil
+	}
+
+	if len(header) < 2 || header[0] != '"' || header[len(header)-1] != '"' {
+		return 0, ErrInvalid
+	}
+
+	// Every representation of a version is based on the same track.
+	tag, _, _ := strings.Cut(header[1:len(header)-1], "-")
+
+	version, err := strconv.ParseInt(tag, 10, 64)
+	if err != nil || version < 1 {
+		return 0, ErrInvalid
+	}
+
+	return version, nil
+}
@@ -0,0 +1,65 @@
+package etag_test
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"lyrics-library/internal/lib/api/etag"
+)
+
+func TestFormat(t *testing.T) {
+	tests := []struct {
+		version int64
+		variant string
+		want    string
+	}{
+		{version: 3, want: `"3"`},
+		{version: 3, variant: "txt", want: `"3-txt"`},
+		{version: 3, variant: "md-pt-br", want: `"3-md-pt-br"`},
+	}
+
+	for _, tt := range tests {
+		if got := etag.Format(tt.version, tt.variant); got != tt.want {
+			t.Errorf("Format(%d, %q) = %s, want %s", tt.version, tt.variant, got, tt.want)
+		}
+	}
+}
+
+func TestIfMatch(t *testing.T) {
+	tests := []struct {
+		header  string
+		want    int64
+		wantErr error
+	}{
+		{header: "", wantErr: etag.ErrMissing},
+		{header: "*", want: 0},
+		{header: `"3"`, want: 3},
+		{header: ` "3" `, want: 3},
+		{header: `"3-txt"`, want: 3},
+		{header: `"3-md-pt-br"`, want: 3},
+		{header: `W/"3"`, wantErr: etag.ErrInvalid},
+		{header: `"3", "4"`, wantErr: etag.ErrInvalid},
+		{header: `"0"`, wantErr: etag.ErrInvalid},
+		{header: `"-txt"`, wantErr: etag.ErrInvalid},
+		{header: `3`, wantErr: etag.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodPatch, "/lyrics", nil)
+		if tt.header != "" {
+			r.Header.Set("If-Match", tt.header)
+		}
+
+		got, err := etag.IfMatch(r)
+		if !errors.Is(err, tt.wantErr) {
+			t.Errorf("IfMatch(%s) error = %v, want %v", tt.header, err, tt.wantErr)
+			continue
+		}
+
+		if got != tt.want {
+			t.Errorf("IfMatch(%s) = %d, want %d", tt.header, got, tt.want)
+		}
+	}
+}
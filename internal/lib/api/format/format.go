@@ -0,0 +1,254 @@
+package format
+
+import (
+	"cmp"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"mime"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/lrc"
+)
+
+const (
+	JSON     = "json"
+	Text     = "txt"
+	CSV      = "csv"
+	Markdown = "md"
+	LRC      = "lrc"
+)
+
+var (
+	ErrUnknownExtension = errors.New("unknown format extension")
+	ErrNotAcceptable    = errors.New("none of the accepted media types is supported")
+)
+
+var contentTypes = map[string]string{
+	JSON:     "application/json",
+	Text:     "text/plain; charset=utf-8",
+	CSV:      "text/csv; charset=utf-8",
+	Markdown: "text/markdown; charset=utf-8",
+	LRC:      "text/plain; charset=utf-8",
+}
+
+var mediaTypes = map[string]string{
+	"application/json": JSON,
+	"text/plain":       Text,
+	"text/csv":         CSV,
+	"text/markdown":    Markdown,
+	"text/x-lrc":       LRC,
+	"application/*":    JSON,
+	"text/*":           Text,
+	"*/*":              JSON,
+}
+
+// Negotiate picks the response format of a track. The URL extension read
+// by middleware.URLFormat wins over the Accept header, no preference means
+// JSON.
+func Negotiate(r *http.Request) (string, error) {
+	if ext, _ := r.Context().Value(middleware.URLFormatCtxKey).(string); ext != "" {
+		if _, ok := contentTypes[ext]; !ok {
+			return "", fmt.Errorf("%w: %q", ErrUnknownExtension, ext)
+		}
+
+		return ext, nil
+	}
+
+	accept := r.Header.Get("Accept")
+	if accept == "" {
+		return JSON, nil
+	}
+
+	for _, mediaType := range acceptedTypes(accept) {
+		if format, ok := mediaTypes[mediaType]; ok {
+			return format, nil
+		}
+	}
+
+	return "", ErrNotAcceptable
+}
+
+type acceptedType struct {
+	mediaType string
+	q         float64
+}
+
+// acceptedTypes returns the media types of an Accept header ordered by
+// their quality, types with q=0 are dropped.
+func acceptedTypes(header string) []string {
+	var accepted []acceptedType
+
+	for _, part := range strings.Split(header, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = parsed
+			}
+		}
+
+		if q > 0 {
+			accepted = append(accepted, acceptedType{mediaType: mediaType, q: q})
+		}
+	}
+
+	slices.SortStableFunc(accepted, func(a, b acceptedType) int {
+		return cmp.Compare(b.q, a.q)
+	})
+
+	result := make([]string, 0, len(accepted))
+	for _, a := range accepted {
+		result = append(result, a.mediaType)
+	}
+
+	return result
+}
+
+// Write renders the track in one of the non-JSON formats. Translations are
+// rendered next to the original lines in every format but LRC, so callers
+// narrow them down with models.Track.WithTranslation first.
+func Write(w http.ResponseWriter, format string, track *models.Track) error {
+	w.Header().Set("Content-Type", contentTypes[format])
+	w.WriteHeader(http.StatusOK)
+
+	switch format {
+	case Text:
+		return writeText(w, track)
+	case CSV:
+		return writeCSV(w, track)
+	case Markdown:
+		return writeMarkdown(w, track)
+	case LRC:
+		return lrc.Format(w, track)
+	default:
+		return fmt.Errorf("%w: %q", ErrUnknownExtension, format)
+	}
+}
+
+// writeText writes the original lines. If the track has translations,
+// every original line is followed by its translations and a blank line.
+func writeText(w io.Writer, track *models.Track) error {
+	langs := sortedLangs(track)
+
+	var b strings.Builder
+
+	for i, line := range track.Lyrics {
+		b.WriteString(line)
+		b.WriteByte('\n')
+
+		if len(langs) == 0 {
+			continue
+		}
+
+		for _, lang := range langs {
+			b.WriteString(translatedLine(track, lang, i))
+			b.WriteByte('\n')
+		}
+
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+// writeCSV writes one row per line: its number, the original and a column
+// per translation.
+func writeCSV(w io.Writer, track *models.Track) error {
+	langs := sortedLangs(track)
+
+	cw := csv.NewWriter(w)
+
+	if err := cw.Write(append([]string{"line", "original"}, langs...)); err != nil {
+		return err
+	}
+
+	for i, line := range track.Lyrics {
+		row := []string{strconv.Itoa(i + 1), line}
+
+		for _, lang := range langs {
+			row = append(row, translatedLine(track, lang, i))
+		}
+
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+
+	return cw.Error()
+}
+
+// writeMarkdown writes a heading and a table with the original next to its
+// translations.
+func writeMarkdown(w io.Writer, track *models.Track) error {
+	langs := sortedLangs(track)
+
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "# %s\n\n*%s*\n\n", escapeMarkdown(track.Title), escapeMarkdown(track.Artist))
+
+	b.WriteString("| Original |")
+	for _, lang := range langs {
+		fmt.Fprintf(&b, " %s |", lang)
+	}
+
+	b.WriteString("\n| --- |")
+	b.WriteString(strings.Repeat(" --- |", len(langs)))
+	b.WriteByte('\n')
+
+	for i, line := range track.Lyrics {
+		fmt.Fprintf(&b, "| %s |", escapeMarkdown(line))
+
+		for _, lang := range langs {
+			fmt.Fprintf(&b, " %s |", escapeMarkdown(translatedLine(track, lang, i)))
+		}
+
+		b.WriteByte('\n')
+	}
+
+	_, err := io.WriteString(w, b.String())
+
+	return err
+}
+
+func sortedLangs(track *models.Track) []string {
+	return slices.Sorted(maps.Keys(track.Translations))
+}
+
+func translatedLine(track *models.Track, lang string, i int) string {
+	lines := track.Translations[lang]
+	if i >= len(lines) {
+		return ""
+	}
+
+	return lines[i]
+}
+
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`|`, `\|`,
+	`*`, `\*`,
+	`_`, `\_`,
+	"`", "\\`",
+	`#`, `\#`,
+)
+
+func escapeMarkdown(s string) string {
+	return markdownEscaper.Replace(s)
+}
@@ -0,0 +1,154 @@
+package format_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi/v5/middleware"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/api/format"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+func TestNegotiate(t *testing.T) {
+	tests := []struct {
+		name    string
+		ext     string
+		accept  string
+		want    string
+		wantErr error
+	}{
+		{name: "no preference", want: format.JSON},
+		{name: "extension", ext: "csv", accept: "application/json", want: format.CSV},
+		{name: "unknown extension", ext: "xml", wantErr: format.ErrUnknownExtension},
+		{name: "exact type", accept: "text/markdown", want: format.Markdown},
+		{name: "lrc", accept: "text/x-lrc", want: format.LRC},
+		{name: "quality order", accept: "text/plain;q=0.5, text/csv;q=0.9", want: format.CSV},
+		{name: "unsupported types are skipped", accept: "image/png, text/csv;q=0.1", want: format.CSV},
+		{name: "wildcard", accept: "*/*", want: format.JSON},
+		{name: "text wildcard", accept: "text/*", want: format.Text},
+		{name: "q=0 is refused", accept: "application/json;q=0", wantErr: format.ErrNotAcceptable},
+		{name: "nothing supported", accept: "image/png", wantErr: format.ErrNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/lyrics", nil)
+			if tt.ext != "" {
+				r = r.WithContext(context.WithValue(r.Context(), middleware.URLFormatCtxKey, tt.ext))
+			}
+
+			if tt.accept != "" {
+				r.Header.Set("Accept", tt.accept)
+			}
+
+			got, err := format.Negotiate(r)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Negotiate() error = %v, want %v", err, tt.wantErr)
+			}
+
+			if got != tt.want {
+				t.Errorf("Negotiate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func newTrack() *models.Track {
+	return &models.Track{
+		Artist: "Daft Punk",
+		Title:  "One More Time",
+		Lyrics: []string{"One more time", "We're gonna | celebrate"},
+		Translations: map[string][]string{
+			"ru": {"Ещё раз", "Мы будем праздновать"},
+			"de": {"Noch einmal"},
+		},
+		Synced:  []models.SyncedLine{{TimeMS: 1000, Text: "One more time"}},
+		Version: 3,
+	}
+}
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		format          string
+		wantContentType string
+		want            string
+	}{
+		{
+			format:          format.Text,
+			wantContentType: "text/plain; charset=utf-8",
+			want:            "One more time\nNoch einmal\nЕщё раз\n\nWe're gonna | celebrate\n\nМы будем праздновать\n\n",
+		},
+		{
+			format:          format.CSV,
+			wantContentType: "text/csv; charset=utf-8",
+			want:            "line,original,de,ru\n1,One more time,Noch einmal,Ещё раз\n2,We're gonna | celebrate,,Мы будем праздновать\n",
+		},
+		{
+			format:          format.Markdown,
+			wantContentType: "text/markdown; charset=utf-8",
+			want: "# One More Time\n\n*Daft Punk*\n\n| Original | de | ru |\n| --- | --- | --- |\n" +
+				"| One more time | Noch einmal | Ещё раз |\n| We're gonna \\| celebrate |  | Мы будем праздновать |\n",
+		},
+		{
+			format:          format.LRC,
+			wantContentType: "text/plain; charset=utf-8",
+			want:            "[ar:Daft Punk]\n[ti:One More Time]\n[00:01.000]One more time\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			if err := format.Write(rec, tt.format, newTrack()); err != nil {
+				t.Fatalf("Write() error = %v", err)
+			}
+
+			if got := rec.Header().Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("Content-Type = %q, want %q", got, tt.wantContentType)
+			}
+
+			if got := rec.Body.String(); got != tt.want {
+				t.Errorf("Write() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderTrackETag(t *testing.T) {
+	tests := []struct {
+		format     string
+		lang       string
+		wantStatus int
+		wantETag   string
+	}{
+		{format: format.JSON, wantStatus: http.StatusOK, wantETag: `"3"`},
+		{format: format.JSON, lang: "ru", wantStatus: http.StatusOK, wantETag: `"3-ru"`},
+		{format: format.Text, wantStatus: http.StatusOK, wantETag: `"3-txt"`},
+		{format: format.Markdown, lang: "de", wantStatus: http.StatusOK, wantETag: `"3-md-de"`},
+		{format: format.LRC, wantStatus: http.StatusOK, wantETag: `"3-lrc"`},
+		{format: format.CSV, lang: "fr", wantStatus: http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format+"-"+tt.lang, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+
+			format.RenderTrack(rec, httptest.NewRequest(http.MethodGet, "/lyrics", nil),
+				slogdiscard.NewDiscardLogger(), newTrack(), tt.format, tt.lang)
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+
+			if got := rec.Header().Get("ETag"); got != tt.wantETag {
+				t.Errorf("ETag = %s, want %s", got, tt.wantETag)
+			}
+		})
+	}
+}
@@ -0,0 +1,75 @@
+package format
+
+import (
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/render"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/api/etag"
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/logger/sl"
+)
+
+// RenderTrack writes track in format, narrowed to the translation into
+// lang when lang is set. The ETag is the track version, tagged with the
+// format and lang when they differ from the full JSON track.
+func RenderTrack(
+	w http.ResponseWriter,
+	r *http.Request,
+	log *slog.Logger,
+	track *models.Track,
+	format, lang string,
+) {
+	w.Header().Add("Vary", "Accept")
+
+	if lang != "" {
+		var ok bool
+
+		track, ok = track.WithTranslation(lang)
+		if !ok {
+			problem.Write(w, r, problem.New(http.StatusNotFound, problem.CodeTranslationNotFound, "translation not found"))
+			return
+		}
+	}
+
+	if format == LRC && len(track.Synced) == 0 {
+		problem.Write(w, r, problem.New(http.StatusNotFound, problem.CodeSyncedLyricsNotFound, "synced lyrics not found"))
+		return
+	}
+
+	if format == Text && lang == "" {
+		// Plain text is the original only, lang turns it into the
+		// interleaved view.
+		track, _ = track.WithTranslation("")
+	}
+
+	etag.Set(w, track.Version, variant(format, lang))
+
+	if format != JSON {
+		if err := Write(w, format, track); err != nil {
+			log.ErrorContext(r.Context(), "failed to write track", slog.String("format", format), sl.Err(err))
+		}
+		return
+	}
+
+	render.Status(r, http.StatusOK)
+
+	render.JSON(w, r, track)
+}
+
+func variant(format, lang string) string {
+	var parts []string
+
+	if format != JSON {
+		parts = append(parts, format)
+	}
+
+	if lang != "" {
+		parts = append(parts, lang)
+	}
+
+	return strings.Join(parts, "-")
+}
@@ -0,0 +1,98 @@
+package problem
+
+import (
+	"encoding/json"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"lyrics-library/internal/lib/logger/slogctx"
+)
+
+const ContentType = "application/problem+json"
+
+// Code identifies a problem for clients. Codes are part of the API and must
+// not change.
+type Code string
+
+const (
+	CodeInvalidRequest       Code = "invalid_request"
+	CodeValidationFailed     Code = "validation_failed"
+	CodeInvalidUUID          Code = "invalid_uuid"
+	CodeInvalidLang          Code = "invalid_lang"
+	CodeInvalidCursor        Code = "invalid_cursor"
+	CodeInvalidLRC           Code = "invalid_lrc"
+	CodeUnauthorized         Code = "unauthorized"
+	CodeForbidden            Code = "forbidden"
+	CodeNotFound             Code = "not_found"
+	CodeTrackNotFound        Code = "track_not_found"
+	CodeTrackExists          Code = "track_exists"
+	CodeArtistTracksNotFound Code = "artist_tracks_not_found"
+	CodeLyricsNotFound       Code = "lyrics_not_found"
+	CodeTranslationNotFound  Code = "translation_not_found"
+	CodeSyncedLyricsNotFound Code = "synced_lyrics_not_found"
+	CodeJobNotFound          Code = "job_not_found"
+	CodeUnknownFormat        Code = "unknown_format"
+	CodeMethodNotAllowed     Code = "method_not_allowed"
+	CodeNotAcceptable        Code = "not_acceptable"
+	CodePayloadTooLarge      Code = "payload_too_large"
+	CodeVersionMismatch      Code = "version_mismatch"
+	CodePreconditionRequired Code = "precondition_required"
+	CodeRateLimited          Code = "rate_limited"
+	CodeQuotaExceeded        Code = "translation_quota_exceeded"
+	CodeInternal             Code = "internal_error"
+	CodeTranslationFailed    Code = "translation_failed"
+	CodeUpstreamUnavailable  Code = "upstream_unavailable"
+)
+
+// Problem is an RFC 7807 problem details object. Type is always
+// about:blank, so Title is the status text and Code tells problems apart.
+type Problem struct {
+	Type      string `json:"type"`
+	Title     string `json:"title"`
+	Status    int    `json:"status"`
+	Detail    string `json:"detail,omitempty"`
+	Instance  string `json:"instance,omitempty"`
+	Code      Code   `json:"code"`
+	RequestID string `json:"request_id,omitempty"`
+
+	// RetryAfter is sent in the Retry-After header when set.
+	RetryAfter time.Duration `json:"-"`
+}
+
+func New(status int, code Code, detail string) *Problem {
+	return &Problem{
+		Type:   "about:blank",
+		Title:  http.StatusText(status),
+		Status: status,
+		Detail: detail,
+		Code:   code,
+	}
+}
+
+// Write sends p with the request path and id filled in.
+func Write(w http.ResponseWriter, r *http.Request, p *Problem) {
+	p.Instance = r.URL.Path
+
+	if id, ok := slogctx.RequestID(r.Context()); ok {
+		p.RequestID = id
+	}
+
+	if p.RetryAfter > 0 {
+		SetRetryAfter(w, p.RetryAfter)
+	}
+
+	w.Header().Set("Content-Type", ContentType)
+	w.WriteHeader(p.Status)
+
+	_ = json.NewEncoder(w).Encode(p)
+}
+
+// SetRetryAfter sets the Retry-After header to d rounded up to whole
+// seconds.
+func SetRetryAfter(w http.ResponseWriter, d time.Duration) {
+	seconds := max(int64(math.Ceil(d.Seconds())), 1)
+
+	w.Header().Set("Retry-After", strconv.FormatInt(seconds, 10))
+}
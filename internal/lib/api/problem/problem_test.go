@@ -0,0 +1,66 @@
+package problem_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/lib/api/problem"
+	"lyrics-library/internal/lib/api/problem/problemtest"
+	"lyrics-library/internal/lib/logger/slogctx"
+)
+
+func TestWrite(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/lyrics/42?lang=ru", nil)
+	r = r.WithContext(slogctx.WithRequestID(r.Context(), "req-1"))
+
+	rec := httptest.NewRecorder()
+
+	p := problem.New(http.StatusTooManyRequests, problem.CodeRateLimited, "slow down")
+	p.RetryAfter = 1500 * time.Millisecond
+
+	problem.Write(rec, r, p)
+
+	got := problemtest.Check(t, rec.Result(), http.StatusTooManyRequests, problem.CodeRateLimited)
+
+	want := problem.Problem{
+		Type:      "about:blank",
+		Title:     "Too Many Requests",
+		Status:    http.StatusTooManyRequests,
+		Detail:    "slow down",
+		Instance:  "/lyrics/42",
+		Code:      problem.CodeRateLimited,
+		RequestID: "req-1",
+	}
+
+	if *got != want {
+		t.Errorf("problem = %+v, want %+v", *got, want)
+	}
+
+	if got := rec.Header().Get("Retry-After"); got != "2" {
+		t.Errorf("Retry-After = %q, want 2", got)
+	}
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	tests := []struct {
+		d    time.Duration
+		want string
+	}{
+		{d: 0, want: "1"},
+		{d: 200 * time.Millisecond, want: "1"},
+		{d: time.Second, want: "1"},
+		{d: 61 * time.Second, want: "61"},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+
+		problem.SetRetryAfter(rec, tt.d)
+
+		if got := rec.Header().Get("Retry-After"); got != tt.want {
+			t.Errorf("SetRetryAfter(%s) = %q, want %q", tt.d, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,35 @@
+// Package problemtest checks problem details responses in tests.
+package problemtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"lyrics-library/internal/lib/api/problem"
+)
+
+// Check fails t unless resp is a problem with wantStatus and wantCode, and
+// returns the decoded problem.
+func Check(t testing.TB, resp *http.Response, wantStatus int, wantCode problem.Code) *problem.Problem {
+	t.Helper()
+
+	if resp.StatusCode != wantStatus {
+		t.Errorf("status = %d, want %d", resp.StatusCode, wantStatus)
+	}
+
+	if got := resp.Header.Get("Content-Type"); got != problem.ContentType {
+		t.Errorf("Content-Type = %q, want %q", got, problem.ContentType)
+	}
+
+	var p problem.Problem
+	if err := json.NewDecoder(resp.Body).Decode(&p); err != nil {
+		t.Fatalf("failed to decode problem: %v", err)
+	}
+
+	if p.Status != wantStatus || p.Code != wantCode {
+		t.Errorf("problem = %d %s, want %d %s", p.Status, p.Code, wantStatus, wantCode)
+	}
+
+	return &p
+}
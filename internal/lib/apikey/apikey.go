@@ -0,0 +1,59 @@
+package apikey
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+)
+
+const (
+	keyPrefix = "llk_"
+	keyBytes  = 32
+
+	// PrefixLen is the length of the key beginning kept in plain text.
+	PrefixLen = len(keyPrefix) + 8
+)
+
+var keyLen = len(keyPrefix) + base64.RawURLEncoding.EncodedLen(keyBytes)
+
+// Generate returns a new random key.
+func Generate() (string, error) {
+	b := make([]byte, keyBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return keyPrefix + base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// Hash returns the hex SHA-256 of key. Keys are random and long, so a fast
+// unsalted hash is enough and lets keys be looked up by their hash.
+func Hash(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
+
+// Prefix returns the beginning of key that identifies it in listings.
+func Prefix(key string) string {
+	if len(key) < PrefixLen {
+		return key
+	}
+
+	return key[:PrefixLen]
+}
+
+// WellFormed reports whether key looks like a key returned by Generate.
+// Only the prefix of such keys is safe to log, anything else may be a
+// secret sent by mistake.
+func WellFormed(key string) bool {
+	if len(key) != keyLen || !strings.HasPrefix(key, keyPrefix) {
+		return false
+	}
+
+	_, err := base64.RawURLEncoding.DecodeString(key[len(keyPrefix):])
+
+	return err == nil
+}
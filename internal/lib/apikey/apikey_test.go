@@ -0,0 +1,49 @@
+package apikey_test
+
+import (
+	"strings"
+	"testing"
+
+	"lyrics-library/internal/lib/apikey"
+)
+
+func TestWellFormed(t *testing.T) {
+	key, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		key  string
+		want bool
+	}{
+		{name: "generated", key: key, want: true},
+		{name: "empty", key: ""},
+		{name: "short", key: "llk_abc"},
+		{name: "too long", key: key + "A"},
+		{name: "wrong prefix", key: "sk__" + key[4:]},
+		{name: "not base64url", key: key[:len(key)-1] + "+"},
+		{name: "bearer token", key: "Bearer " + strings.Repeat("x", 40)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := apikey.WellFormed(tt.key); got != tt.want {
+				t.Errorf("WellFormed(%q) = %v, want %v", tt.key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrefix(t *testing.T) {
+	key, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	prefix := apikey.Prefix(key)
+	if len(prefix) != apikey.PrefixLen || !strings.HasPrefix(key, prefix) {
+		t.Errorf("Prefix(%q) = %q", key, prefix)
+	}
+}
@@ -0,0 +1,88 @@
+package background
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrClosed = errors.New("background group is closed")
+
+// Group runs fire-and-forget tasks that must not be bound to the request
+// that started them, and lets the owner wait for them on shutdown.
+type Group struct {
+	wg      sync.WaitGroup
+	mu      sync.Mutex
+	closed  bool
+	timeout time.Duration
+
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// New returns a group whose tasks are each limited to timeout.
+func New(timeout time.Duration) *Group {
+	ctx, cancel := context.WithCancel(context.Background())
+
+	return &Group{
+		timeout: timeout,
+		ctx:     ctx,
+		cancel:  cancel,
+	}
+}
+
+// Go runs fn in a new goroutine. The context passed to fn keeps the values
+// of parent (trace span, request attributes) but not its cancellation, so
+// the task outlives the request. It is cancelled after the group timeout
+// or when Shutdown gives up waiting. Go returns ErrClosed once Shutdown
+// has been called.
+func (g *Group) Go(parent context.Context, fn func(ctx context.Context)) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if g.closed {
+		return ErrClosed
+	}
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(parent), g.timeout)
+		defer cancel()
+
+		stop := context.AfterFunc(g.ctx, cancel)
+		defer stop()
+
+		fn(ctx)
+	}()
+
+	return nil
+}
+
+// Shutdown stops accepting tasks and waits for the running ones. If ctx is
+// done first, the remaining tasks are cancelled and ctx's error returned.
+func (g *Group) Shutdown(ctx context.Context) error {
+	g.mu.Lock()
+	g.closed = true
+	g.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		g.wg.Wait()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+		g.cancel()
+
+		return nil
+	case <-ctx.Done():
+		g.cancel()
+
+		return ctx.Err()
+	}
+}
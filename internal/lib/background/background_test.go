@@ -0,0 +1,131 @@
+package background_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/lib/background"
+)
+
+type ctxKey struct{}
+
+func TestGoOutlivesParent(t *testing.T) {
+	g := background.New(time.Second)
+
+	parent, cancel := context.WithCancel(context.WithValue(context.Background(), ctxKey{}, "value"))
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	err := g.Go(parent, func(ctx context.Context) {
+		close(started)
+
+		select {
+		case <-ctx.Done():
+			result <- ctx.Err()
+		case <-time.After(50 * time.Millisecond):
+			if ctx.Value(ctxKey{}) != "value" {
+				result <- errors.New("parent values are lost")
+				return
+			}
+
+			result <- nil
+		}
+	})
+	if err != nil {
+		t.Fatalf("Go() error = %v", err)
+	}
+
+	<-started
+	cancel()
+
+	if err := <-result; err != nil {
+		t.Errorf("task error = %v, want it to finish after the parent is cancelled", err)
+	}
+}
+
+func TestGoTimeout(t *testing.T) {
+	g := background.New(20 * time.Millisecond)
+
+	result := make(chan error, 1)
+
+	_ = g.Go(context.Background(), func(ctx context.Context) {
+		<-ctx.Done()
+		result <- ctx.Err()
+	})
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("task error = %v, want %v", err, context.DeadlineExceeded)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("task is not cancelled after the group timeout")
+	}
+}
+
+func TestShutdownDrainsTasks(t *testing.T) {
+	g := background.New(time.Second)
+
+	var finished atomic.Int32
+
+	for range 3 {
+		_ = g.Go(context.Background(), func(ctx context.Context) {
+			select {
+			case <-ctx.Done():
+			case <-time.After(30 * time.Millisecond):
+				finished.Add(1)
+			}
+		})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	if err := g.Shutdown(ctx); err != nil {
+		t.Fatalf("Shutdown() error = %v", err)
+	}
+
+	if finished.Load() != 3 {
+		t.Errorf("%d tasks finished before Shutdown returned, want 3", finished.Load())
+	}
+
+	if err := g.Go(context.Background(), func(ctx context.Context) {}); !errors.Is(err, background.ErrClosed) {
+		t.Errorf("Go() after Shutdown error = %v, want %v", err, background.ErrClosed)
+	}
+}
+
+func TestShutdownCancelsTasksAfterDeadline(t *testing.T) {
+	g := background.New(time.Minute)
+
+	started := make(chan struct{})
+	result := make(chan error, 1)
+
+	_ = g.Go(context.Background(), func(ctx context.Context) {
+		close(started)
+
+		<-ctx.Done()
+		result <- ctx.Err()
+	})
+
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	if err := g.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	select {
+	case err := <-result:
+		if !errors.Is(err, context.Canceled) {
+			t.Errorf("task error = %v, want %v", err, context.Canceled)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("task is not cancelled after the shutdown deadline")
+	}
+}
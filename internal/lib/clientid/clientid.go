@@ -0,0 +1,23 @@
+package clientid
+
+import "context"
+
+// Anonymous identifies work that isn't attributed to any client.
+const Anonymous = "anonymous"
+
+type ctxKey struct{}
+
+// WithContext returns a copy of ctx that carries the client id, an API key
+// ("key:<id>") or a remote address ("ip:<addr>").
+func WithContext(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, ctxKey{}, id)
+}
+
+// FromContext returns the client id stored in ctx or Anonymous.
+func FromContext(ctx context.Context) string {
+	if id, ok := ctx.Value(ctxKey{}).(string); ok && id != "" {
+		return id
+	}
+
+	return Anonymous
+}
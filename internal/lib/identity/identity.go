@@ -0,0 +1,25 @@
+package identity
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+const separator = "\x1f"
+
+// Normalize brings s to a canonical form: Unicode NFC, case-folded and with
+// runs of whitespace collapsed into a single space.
+func Normalize(s string) string {
+	s = norm.NFC.String(s)
+	s = cases.Fold().String(s)
+
+	return strings.Join(strings.Fields(s), " ")
+}
+
+// TrackKey returns the identity of a track. Two tracks with the same key
+// are considered the same song.
+func TrackKey(artist, title string) string {
+	return Normalize(artist) + separator + Normalize(title)
+}
@@ -0,0 +1,39 @@
+package identity_test
+
+import (
+	"testing"
+
+	"lyrics-library/internal/lib/identity"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "Daft Punk", want: "daft punk"},
+		{in: "  Daft \t\n Punk  ", want: "daft punk"},
+		{in: "BEYONCÉ", want: "beyoncé"},
+		{in: "Beyonce\u0301", want: "beyonc\u00e9"},
+		{in: "Straße", want: "strasse"},
+		{in: "Σίσυφος", want: "σίσυφοσ"},
+		{in: "", want: ""},
+	}
+
+	for _, tt := range tests {
+		if got := identity.Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestTrackKey(t *testing.T) {
+	if identity.TrackKey("Daft Punk", "One  More Time") != identity.TrackKey("daft punk", "ONE MORE TIME") {
+		t.Error("TrackKey() differs for the same song")
+	}
+
+	// The separator keeps the artist and the title apart.
+	if identity.TrackKey("a b", "c") == identity.TrackKey("a", "b c") {
+		t.Error("TrackKey() is the same for different songs")
+	}
+}
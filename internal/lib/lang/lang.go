@@ -0,0 +1,20 @@
+package lang
+
+import (
+	"regexp"
+	"strings"
+)
+
+var codeRegexp = regexp.MustCompile(`^[a-z]{2,3}(-[a-z0-9]{2,8})?$`)
+
+// Normalize lower-cases a language code, so "pt-BR" and "pt-br" are the
+// same language.
+func Normalize(code string) string {
+	return strings.ToLower(strings.TrimSpace(code))
+}
+
+// Valid reports whether code looks like a BCP 47 language code, e.g. "ru"
+// or "pt-br". The code is expected to be normalized.
+func Valid(code string) bool {
+	return codeRegexp.MatchString(code)
+}
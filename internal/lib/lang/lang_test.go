@@ -0,0 +1,51 @@
+package lang_test
+
+import (
+	"testing"
+
+	"lyrics-library/internal/lib/lang"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+	}{
+		{in: "ru", want: "ru"},
+		{in: " pt-BR ", want: "pt-br"},
+		{in: "ZH-Hant", want: "zh-hant"},
+	}
+
+	for _, tt := range tests {
+		if got := lang.Normalize(tt.in); got != tt.want {
+			t.Errorf("Normalize(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestValid(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{code: "ru", want: true},
+		{code: "fil", want: true},
+		{code: "pt-br", want: true},
+		{code: "zh-hant", want: true},
+		{code: "es-419", want: true},
+		{code: ""},
+		{code: "r"},
+		{code: "russ"},
+		{code: "pt-BR"},
+		{code: "pt_br"},
+		{code: "pt-"},
+		{code: "en-toolongtag"},
+		{code: "nope!"},
+	}
+
+	for _, tt := range tests {
+		if got := lang.Valid(tt.code); got != tt.want {
+			t.Errorf("Valid(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package slogctx_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+
+	"lyrics-library/internal/lib/logger/slogctx"
+)
+
+var spanContext = trace.NewSpanContext(trace.SpanContextConfig{
+	TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+	SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+	TraceFlags: trace.FlagsSampled,
+})
+
+func logLine(t *testing.T, log func(l *slog.Logger)) map[string]any {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	l := slog.New(slogctx.NewHandler(slog.NewJSONHandler(&buf, nil)))
+	log(l)
+
+	var line map[string]any
+	if err := json.Unmarshal(buf.Bytes(), &line); err != nil {
+		t.Fatalf("log line %q: %v", buf.String(), err)
+	}
+
+	return line
+}
+
+func TestHandler(t *testing.T) {
+	ctx := slogctx.WithRequestID(context.Background(), "req-1")
+	ctx = trace.ContextWithSpanContext(ctx, spanContext)
+
+	tests := []struct {
+		name          string
+		log           func(l *slog.Logger)
+		wantRequestID any
+		wantTraceID   any
+	}{
+		{
+			name:          "context attributes",
+			log:           func(l *slog.Logger) { l.InfoContext(ctx, "msg") },
+			wantRequestID: "req-1",
+			wantTraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name:          "kept by With",
+			log:           func(l *slog.Logger) { l.With(slog.String("op", "op")).InfoContext(ctx, "msg") },
+			wantRequestID: "req-1",
+			wantTraceID:   "4bf92f3577b34da6a3ce929d0e0e4736",
+		},
+		{
+			name: "without context",
+			log:  func(l *slog.Logger) { l.Info("msg") },
+		},
+		{
+			name: "request id only",
+			log: func(l *slog.Logger) {
+				l.InfoContext(slogctx.WithRequestID(context.Background(), "req-2"), "msg")
+			},
+			wantRequestID: "req-2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			line := logLine(t, tt.log)
+
+			if got := line["request_id"]; got != tt.wantRequestID {
+				t.Errorf("request_id = %v, want %v", got, tt.wantRequestID)
+			}
+
+			if got := line["trace_id"]; got != tt.wantTraceID {
+				t.Errorf("trace_id = %v, want %v", got, tt.wantTraceID)
+			}
+		})
+	}
+}
+
+func TestRequestID(t *testing.T) {
+	if _, ok := slogctx.RequestID(context.Background()); ok {
+		t.Error("RequestID() ok = true for a context without an id")
+	}
+
+	id, ok := slogctx.RequestID(slogctx.WithRequestID(context.Background(), "req-1"))
+	if !ok || id != "req-1" {
+		t.Errorf("RequestID() = %q, %v, want req-1, true", id, ok)
+	}
+}
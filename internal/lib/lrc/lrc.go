@@ -0,0 +1,160 @@
+package lrc
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"lyrics-library/internal/domain/models"
+)
+
+var (
+	ErrInvalid = errors.New("invalid lrc")
+	ErrEmpty   = errors.New("lrc has no timed lines")
+)
+
+// MaxLines limits the number of timed lines accepted from one file.
+const MaxLines = 5000
+
+var (
+	timeTag = regexp.MustCompile(`^\[(\d{1,3}):(\d{2})(?:[.:](\d{1,3}))?\]`)
+	metaTag = regexp.MustCompile(`^\[([a-zA-Z#]+):(.*)\]$`)
+)
+
+// Parse reads an LRC file. Lines may carry several time tags, the offset
+// tag is applied and the result is ordered by time. Other metadata tags
+// are ignored.
+func Parse(r io.Reader) ([]models.SyncedLine, error) {
+	var (
+		lines  []models.SyncedLine
+		offset int64
+	)
+
+	scanner := bufio.NewScanner(r)
+
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if n == 1 {
+			line = strings.TrimPrefix(line, "\uFEFF")
+		}
+
+		if line == "" {
+			continue
+		}
+
+		var times []int64
+
+		for {
+			m := timeTag.FindStringSubmatch(line)
+			if m == nil {
+				break
+			}
+
+			ms, err := parseTime(m[1], m[2], m[3])
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d: %w", ErrInvalid, n, err)
+			}
+
+			times = append(times, ms)
+			line = line[len(m[0]):]
+		}
+
+		if len(times) == 0 {
+			m := metaTag.FindStringSubmatch(line)
+			if m == nil {
+				return nil, fmt.Errorf("%w: line %d: expected a time or metadata tag", ErrInvalid, n)
+			}
+
+			if strings.EqualFold(m[1], "offset") {
+				value, err := strconv.ParseInt(strings.TrimSpace(m[2]), 10, 64)
+				if err != nil {
+					return nil, fmt.Errorf("%w: line %d: bad offset %q", ErrInvalid, n, m[2])
+				}
+
+				offset = value
+			}
+
+			continue
+		}
+
+		text := strings.TrimSpace(line)
+
+		for _, ms := range times {
+			lines = append(lines, models.SyncedLine{TimeMS: ms, Text: text})
+		}
+
+		if len(lines) > MaxLines {
+			return nil, fmt.Errorf("%w: more than %d lines", ErrInvalid, MaxLines)
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrInvalid, err)
+	}
+
+	if len(lines) == 0 {
+		return nil, ErrEmpty
+	}
+
+	// A positive offset makes the lyrics appear sooner.
+	for i := range lines {
+		lines[i].TimeMS = max(lines[i].TimeMS-offset, 0)
+	}
+
+	slices.SortStableFunc(lines, func(a, b models.SyncedLine) int {
+		return cmp.Compare(a.TimeMS, b.TimeMS)
+	})
+
+	return lines, nil
+}
+
+func parseTime(minutes, seconds, fraction string) (int64, error) {
+	m, _ := strconv.ParseInt(minutes, 10, 64)
+	sec, _ := strconv.ParseInt(seconds, 10, 64)
+
+	if sec > 59 {
+		return 0, fmt.Errorf("bad seconds %q", seconds)
+	}
+
+	var ms int64
+	if fraction != "" {
+		ms, _ = strconv.ParseInt(fraction, 10, 64)
+
+		// .5 is 500ms, .05 is 50ms, .005 is 5ms.
+		for i := len(fraction); i < 3; i++ {
+			ms *= 10
+		}
+	}
+
+	return (m*60+sec)*1000 + ms, nil
+}
+
+// Format writes the track's synced lyrics as an LRC file with the artist
+// and title tags. Times keep the milliseconds Parse reads, so a file
+// written back is parsed to the same lines.
+func Format(w io.Writer, track *models.Track) error {
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "[ar:%s]\n", oneLine(track.Artist))
+	fmt.Fprintf(bw, "[ti:%s]\n", oneLine(track.Title))
+
+	for _, line := range track.Synced {
+		fmt.Fprintf(bw, "[%s]%s\n", formatTime(line.TimeMS), oneLine(line.Text))
+	}
+
+	return bw.Flush()
+}
+
+func formatTime(ms int64) string {
+	return fmt.Sprintf("%02d:%02d.%03d", ms/60000, ms/1000%60, ms%1000)
+}
+
+func oneLine(s string) string {
+	return strings.Join(strings.Fields(s), " ")
+}
@@ -0,0 +1,182 @@
+package lrc_test
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/lrc"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want []models.SyncedLine
+	}{
+		{
+			name: "ordered by time",
+			in:   "[00:02.00]second\n\n[00:01.00] first \n",
+			want: []models.SyncedLine{{TimeMS: 1000, Text: "first"}, {TimeMS: 2000, Text: "second"}},
+		},
+		{
+			name: "several time tags on one line",
+			in:   "[00:01.00][00:03.00]chorus\n[00:02.00]verse\n",
+			want: []models.SyncedLine{
+				{TimeMS: 1000, Text: "chorus"},
+				{TimeMS: 2000, Text: "verse"},
+				{TimeMS: 3000, Text: "chorus"},
+			},
+		},
+		{
+			name: "fractions of 1, 2 and 3 digits",
+			in:   "[00:01.5]a\n[00:02.05]b\n[00:03.005]c\n[00:04:25]d\n[00:05]e\n",
+			want: []models.SyncedLine{
+				{TimeMS: 1500, Text: "a"},
+				{TimeMS: 2050, Text: "b"},
+				{TimeMS: 3005, Text: "c"},
+				{TimeMS: 4250, Text: "d"},
+				{TimeMS: 5000, Text: "e"},
+			},
+		},
+		{
+			name: "minutes",
+			in:   "[01:02.00]a\n[100:00.00]b\n",
+			want: []models.SyncedLine{{TimeMS: 62000, Text: "a"}, {TimeMS: 6000000, Text: "b"}},
+		},
+		{
+			name: "positive offset makes lines sooner",
+			in:   "[offset:+500]\n[00:00.20]a\n[00:01.00]b\n",
+			want: []models.SyncedLine{{TimeMS: 0, Text: "a"}, {TimeMS: 500, Text: "b"}},
+		},
+		{
+			name: "negative offset makes lines later",
+			in:   "[00:01.00]a\n[OFFSET: -250]\n",
+			want: []models.SyncedLine{{TimeMS: 1250, Text: "a"}},
+		},
+		{
+			name: "metadata and empty lines are skipped",
+			in:   "[ar:Artist]\n[ti:Title]\n[#:comment]\n[00:01.00]\n",
+			want: []models.SyncedLine{{TimeMS: 1000, Text: ""}},
+		},
+		{
+			name: "leading BOM",
+			in:   "\uFEFF[ar:Artist]\n[00:01.00]a\n",
+			want: []models.SyncedLine{{TimeMS: 1000, Text: "a"}},
+		},
+		{
+			name: "CRLF line endings",
+			in:   "[00:01.00]a\r\n[00:02.00]b\r\n",
+			want: []models.SyncedLine{{TimeMS: 1000, Text: "a"}, {TimeMS: 2000, Text: "b"}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := lrc.Parse(strings.NewReader(tt.in))
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		in      string
+		wantErr error
+	}{
+		{name: "empty", in: "", wantErr: lrc.ErrEmpty},
+		{name: "metadata only", in: "[ar:Artist]\n[ti:Title]\n", wantErr: lrc.ErrEmpty},
+		{name: "seconds above 59", in: "[00:60.00]a\n", wantErr: lrc.ErrInvalid},
+		{name: "one digit seconds", in: "[00:1.00]a\n", wantErr: lrc.ErrInvalid},
+		{name: "four digit fraction", in: "[00:01.0000]a\n", wantErr: lrc.ErrInvalid},
+		{name: "missing brackets", in: "00:01.00 a\n", wantErr: lrc.ErrInvalid},
+		{name: "plain text", in: "[00:01.00]a\nno tag\n", wantErr: lrc.ErrInvalid},
+		{name: "bad offset", in: "[offset:soon]\n[00:01.00]a\n", wantErr: lrc.ErrInvalid},
+		{name: "BOM after the first line", in: "[00:01.00]a\n\uFEFF[00:02.00]b\n", wantErr: lrc.ErrInvalid},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := lrc.Parse(strings.NewReader(tt.in)); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseMaxLines(t *testing.T) {
+	lines, err := lrc.Parse(strings.NewReader(strings.Repeat("[00:01.00]a\n", lrc.MaxLines)))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if len(lines) != lrc.MaxLines {
+		t.Errorf("Parse() lines = %d, want %d", len(lines), lrc.MaxLines)
+	}
+
+	tooMany := []string{
+		strings.Repeat("[00:01.00]a\n", lrc.MaxLines+1),
+		// Each time tag is a line of its own.
+		strings.Repeat("[00:01.00]", lrc.MaxLines+1) + "a\n",
+	}
+
+	for _, in := range tooMany {
+		if _, err := lrc.Parse(strings.NewReader(in)); !errors.Is(err, lrc.ErrInvalid) {
+			t.Errorf("Parse() error = %v, want %v", err, lrc.ErrInvalid)
+		}
+	}
+}
+
+func TestFormat(t *testing.T) {
+	track := &models.Track{
+		Artist: "Daft\nPunk",
+		Title:  "One More Time",
+		Synced: []models.SyncedLine{
+			{TimeMS: 1005, Text: "first"},
+			{TimeMS: 62500, Text: "second  line\r\n"},
+			{TimeMS: 6000000, Text: ""},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := lrc.Format(&buf, track); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	want := "[ar:Daft Punk]\n[ti:One More Time]\n[00:01.005]first\n[01:02.500]second line\n[100:00.000]\n"
+	if got := buf.String(); got != want {
+		t.Errorf("Format() = %q, want %q", got, want)
+	}
+}
+
+func TestFormatRoundTrip(t *testing.T) {
+	var synced []models.SyncedLine
+	for ms := int64(0); ms < 3000; ms += 7 {
+		synced = append(synced, models.SyncedLine{TimeMS: ms, Text: fmt.Sprintf("line %d", ms)})
+	}
+
+	var buf bytes.Buffer
+	if err := lrc.Format(&buf, &models.Track{Artist: "artist", Title: "title", Synced: synced}); err != nil {
+		t.Fatalf("Format() error = %v", err)
+	}
+
+	got, err := lrc.Parse(&buf)
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	if !slices.Equal(got, synced) {
+		t.Errorf("Parse(Format()) lost precision")
+	}
+}
@@ -0,0 +1,143 @@
+package metrics
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "lyrics_library"
+
+const (
+	CacheTrack        = "track"
+	CacheArtistTracks = "artist_tracks"
+)
+
+// Metrics holds the Prometheus collectors of the service. A nil *Metrics
+// is valid and records nothing.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests     *prometheus.CounterVec
+	httpDuration     *prometheus.HistogramVec
+	cacheLookups     *prometheus.CounterVec
+	dbQueryDuration  *prometheus.HistogramVec
+	upstreamDuration *prometheus.HistogramVec
+	upstreamErrors   *prometheus.CounterVec
+}
+
+func New() *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "Number of HTTP requests by route, method and status.",
+		}, []string{"route", "method", "status"}),
+
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP request latency by route, method and status.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"route", "method", "status"}),
+
+		cacheLookups: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "cache_lookups_total",
+			Help:      "Number of cache lookups by cached value and result (hit or miss).",
+		}, []string{"cache", "result"}),
+
+		dbQueryDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "db_query_duration_seconds",
+			Help:      "Postgres query latency by storage method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+
+		upstreamDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "upstream_request_duration_seconds",
+			Help:      "Latency of calls to upstream APIs.",
+			Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10},
+		}, []string{"upstream"}),
+
+		upstreamErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "upstream_errors_total",
+			Help:      "Number of failed calls to upstream APIs.",
+		}, []string{"upstream"}),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.cacheLookups,
+		m.dbQueryDuration,
+		m.upstreamDuration,
+		m.upstreamErrors,
+	)
+
+	return m
+}
+
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{})
+}
+
+func (m *Metrics) ObserveHTTPRequest(route, method string, status int, duration time.Duration) {
+	if m == nil {
+		return
+	}
+
+	labels := prometheus.Labels{
+		"route":  route,
+		"method": method,
+		"status": strconv.Itoa(status),
+	}
+
+	m.httpRequests.With(labels).Inc()
+	m.httpDuration.With(labels).Observe(duration.Seconds())
+}
+
+func (m *Metrics) ObserveCacheLookup(cache string, hit bool) {
+	if m == nil {
+		return
+	}
+
+	result := "miss"
+	if hit {
+		result = "hit"
+	}
+
+	m.cacheLookups.WithLabelValues(cache, result).Inc()
+}
+
+// ObserveQuery records the time since start. It is meant to be deferred
+// at the top of a storage method.
+func (m *Metrics) ObserveQuery(method string, start time.Time) {
+	if m == nil {
+		return
+	}
+
+	m.dbQueryDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+}
+
+func (m *Metrics) ObserveUpstreamCall(upstream string, start time.Time, failed bool) {
+	if m == nil {
+		return
+	}
+
+	m.upstreamDuration.WithLabelValues(upstream).Observe(time.Since(start).Seconds())
+
+	if failed {
+		m.upstreamErrors.WithLabelValues(upstream).Inc()
+	}
+}
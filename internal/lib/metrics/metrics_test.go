@@ -0,0 +1,62 @@
+package metrics_test
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/lib/metrics"
+)
+
+func scrape(t *testing.T, m *metrics.Metrics) string {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	m.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	body, _ := io.ReadAll(rec.Body)
+
+	return string(body)
+}
+
+func TestMetrics(t *testing.T) {
+	m := metrics.New()
+
+	m.ObserveCacheLookup(metrics.CacheTrack, true)
+	m.ObserveCacheLookup(metrics.CacheTrack, false)
+	m.ObserveCacheLookup(metrics.CacheArtistTracks, false)
+	m.ObserveQuery("TrackByUUID", time.Now())
+	m.ObserveUpstreamCall("lyricsovh", time.Now(), false)
+	m.ObserveUpstreamCall("lyricsovh", time.Now(), true)
+
+	body := scrape(t, m)
+
+	want := []string{
+		`lyrics_library_cache_lookups_total{cache="track",result="hit"} 1`,
+		`lyrics_library_cache_lookups_total{cache="track",result="miss"} 1`,
+		`lyrics_library_cache_lookups_total{cache="artist_tracks",result="miss"} 1`,
+		`lyrics_library_db_query_duration_seconds_count{method="TrackByUUID"} 1`,
+		`lyrics_library_upstream_request_duration_seconds_count{upstream="lyricsovh"} 2`,
+		`lyrics_library_upstream_errors_total{upstream="lyricsovh"} 1`,
+		`go_goroutines`,
+	}
+
+	for _, line := range want {
+		if !strings.Contains(body, line) {
+			t.Errorf("metrics don't contain %s", line)
+		}
+	}
+}
+
+func TestNilMetrics(t *testing.T) {
+	var m *metrics.Metrics
+
+	// A nil *Metrics records nothing and doesn't panic.
+	m.ObserveHTTPRequest("/lyrics", http.MethodGet, http.StatusOK, time.Second)
+	m.ObserveCacheLookup(metrics.CacheTrack, true)
+	m.ObserveQuery("TrackByUUID", time.Now())
+	m.ObserveUpstreamCall("lyricsovh", time.Now(), true)
+}
@@ -0,0 +1,45 @@
+package pagination
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultLimit = 20
+	MaxLimit     = 100
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor points at the last row of the previous page. It is handed to
+// clients as an opaque string, so its fields may change between releases.
+type Cursor struct {
+	Sort      string    `json:"s"`
+	Title     string    `json:"t,omitempty"`
+	CreatedAt time.Time `json:"c,omitempty"`
+	ID        int64     `json:"i"`
+}
+
+func Encode(c Cursor) string {
+	data, _ := json.Marshal(c)
+
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func Decode(s string) (Cursor, error) {
+	var c Cursor
+
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(data, &c); err != nil {
+		return c, ErrInvalidCursor
+	}
+
+	return c, nil
+}
@@ -0,0 +1,42 @@
+package pagination_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/lib/pagination"
+)
+
+func TestEncodeDecode(t *testing.T) {
+	cursors := []pagination.Cursor{
+		{Sort: "title", Title: "one more time", ID: 42},
+		{Sort: "created_at", CreatedAt: time.Date(2026, 10, 16, 12, 30, 0, 123456789, time.UTC), ID: 7},
+	}
+
+	for _, want := range cursors {
+		got, err := pagination.Decode(pagination.Encode(want))
+		if err != nil {
+			t.Fatalf("Decode() error = %v", err)
+		}
+
+		if got != want {
+			t.Errorf("Decode(Encode(%+v)) = %+v", want, got)
+		}
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	cursors := []string{
+		"not base64!",
+		base64.RawURLEncoding.EncodeToString([]byte("not json")),
+		base64.StdEncoding.EncodeToString([]byte(`{"s":"title","i":1}`)),
+	}
+
+	for _, cursor := range cursors {
+		if _, err := pagination.Decode(cursor); !errors.Is(err, pagination.ErrInvalidCursor) {
+			t.Errorf("Decode(%q) error = %v, want %v", cursor, err, pagination.ErrInvalidCursor)
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"lyrics-library/internal/lib/tracing"
+)
+
+func setup(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	recorder := tracetest.NewSpanRecorder()
+
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	return recorder
+}
+
+func TestFail(t *testing.T) {
+	recorder := setup(t)
+
+	_, span := tracing.Start(context.Background(), "service.track.Save")
+	tracing.Fail(span, errors.New("lyrics not found"))
+	span.End()
+
+	spans := recorder.Ended()
+	if len(spans) != 1 {
+		t.Fatalf("spans = %d, want 1", len(spans))
+	}
+
+	if got := spans[0].Status(); got.Code != codes.Error || got.Description != "lyrics not found" {
+		t.Errorf("status = %+v, want an error", got)
+	}
+
+	if got := len(spans[0].Events()); got != 1 {
+		t.Errorf("events = %d, want the recorded error", got)
+	}
+}
+
+func TestTransportPropagatesTrace(t *testing.T) {
+	setup(t)
+
+	var traceparent string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparent = r.Header.Get("traceparent")
+	}))
+	t.Cleanup(srv.Close)
+
+	ctx, span := tracing.Start(context.Background(), "service.api.lyricsovh.Lyrics")
+	defer span.End()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+
+	resp, err := (&http.Client{Transport: tracing.Transport(nil)}).Do(req)
+	if err != nil {
+		t.Fatalf("Do() error = %v", err)
+	}
+	resp.Body.Close()
+
+	if !strings.Contains(traceparent, span.SpanContext().TraceID().String()) {
+		t.Errorf("traceparent = %q, want trace %s", traceparent, span.SpanContext().TraceID())
+	}
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/apikey"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+	"lyrics-library/internal/storage"
+)
+
+type KeyStorage interface {
+	CreateAPIKey(ctx context.Context, key *models.APIKey, hash string) error
+	APIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error)
+	APIKeys(ctx context.Context) ([]*models.APIKey, error)
+	RevokeAPIKey(ctx context.Context, id string) error
+}
+
+var (
+	ErrInvalidKey  = errors.New("invalid api key")
+	ErrKeyNotFound = errors.New("api key not found")
+	ErrNoScopes    = errors.New("at least one scope is required")
+)
+
+type AuthService struct {
+	log        *slog.Logger
+	keyStorage KeyStorage
+}
+
+func New(log *slog.Logger, keyStorage KeyStorage) *AuthService {
+	return &AuthService{
+		log:        log,
+		keyStorage: keyStorage,
+	}
+}
+
+// Authenticate returns the key's description if key exists and hasn't
+// been revoked.
+func (s *AuthService) Authenticate(ctx context.Context, key string) (*models.APIKey, error) {
+	const op = "service.auth.Authenticate"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	// Anything but a well-formed key may be a secret pasted into the wrong
+	// header, so only its length is logged.
+	keyAttr := slog.Int("key_length", len(key))
+	if apikey.WellFormed(key) {
+		keyAttr = slog.String("prefix", apikey.Prefix(key))
+	}
+
+	log := s.log.With(slog.String("op", op), keyAttr)
+
+	apiKey, err := s.keyStorage.APIKeyByHash(ctx, apikey.Hash(key))
+	if err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			log.WarnContext(ctx, "unknown api key")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidKey)
+		}
+
+		log.ErrorContext(ctx, "failed to get api key", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if apiKey.RevokedAt != nil {
+		log.WarnContext(ctx, "revoked api key", slog.String("key_id", apiKey.ID))
+
+		return nil, fmt.Errorf("%s: %w", op, ErrInvalidKey)
+	}
+
+	return apiKey, nil
+}
+
+// CreateKey generates a key with the given scopes. The returned plain key
+// is not stored and can't be recovered later.
+func (s *AuthService) CreateKey(
+	ctx context.Context,
+	name string,
+	scopes []models.Scope,
+) (*models.APIKey, string, error) {
+	const op = "service.auth.CreateKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op), slog.String("name", name))
+
+	if len(scopes) == 0 {
+		return nil, "", fmt.Errorf("%s: %w", op, ErrNoScopes)
+	}
+
+	key, err := apikey.Generate()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	apiKey := &models.APIKey{
+		Name:   name,
+		Prefix: apikey.Prefix(key),
+		Scopes: scopes,
+	}
+
+	if err := s.keyStorage.CreateAPIKey(ctx, apiKey, apikey.Hash(key)); err != nil {
+		log.ErrorContext(ctx, "failed to create api key", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, "", fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.InfoContext(ctx, "api key created", slog.String("key_id", apiKey.ID))
+
+	return apiKey, key, nil
+}
+
+func (s *AuthService) Keys(ctx context.Context) ([]*models.APIKey, error) {
+	const op = "service.auth.Keys"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	keys, err := s.keyStorage.APIKeys(ctx)
+	if err != nil {
+		s.log.ErrorContext(ctx, "failed to list api keys", slog.String("op", op), sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+func (s *AuthService) RevokeKey(ctx context.Context, id string) error {
+	const op = "service.auth.RevokeKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op), slog.String("key_id", id))
+
+	if err := s.keyStorage.RevokeAPIKey(ctx, id); err != nil {
+		if errors.Is(err, storage.ErrAPIKeyNotFound) {
+			return fmt.Errorf("%s: %w", op, ErrKeyNotFound)
+		}
+
+		log.ErrorContext(ctx, "failed to revoke api key", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.InfoContext(ctx, "api key revoked")
+
+	return nil
+}
@@ -0,0 +1,86 @@
+package auth_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/apikey"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/service/auth"
+	"lyrics-library/internal/storage/memory"
+)
+
+func TestAuthenticate(t *testing.T) {
+	ctx := context.Background()
+	service := auth.New(slogdiscard.NewDiscardLogger(), memory.New())
+
+	created, key, err := service.CreateKey(ctx, "editor", []models.Scope{models.ScopeWrite})
+	if err != nil {
+		t.Fatalf("CreateKey() error = %v", err)
+	}
+
+	got, err := service.Authenticate(ctx, key)
+	if err != nil {
+		t.Fatalf("Authenticate() error = %v", err)
+	}
+
+	if got.ID != created.ID {
+		t.Errorf("Authenticate() key id = %q, want %q", got.ID, created.ID)
+	}
+
+	if err := service.RevokeKey(ctx, created.ID); err != nil {
+		t.Fatalf("RevokeKey() error = %v", err)
+	}
+
+	if _, err := service.Authenticate(ctx, key); !errors.Is(err, auth.ErrInvalidKey) {
+		t.Errorf("Authenticate() of a revoked key error = %v, want %v", err, auth.ErrInvalidKey)
+	}
+}
+
+func TestAuthenticateLogsNoSecrets(t *testing.T) {
+	unknown, err := apikey.Generate()
+	if err != nil {
+		t.Fatalf("Generate() error = %v", err)
+	}
+
+	tests := []struct {
+		name       string
+		key        string
+		wantLogged string
+	}{
+		{name: "unknown key", key: unknown, wantLogged: "prefix=" + apikey.Prefix(unknown)},
+		{name: "short secret", key: "hunter2", wantLogged: "key_length=7"},
+		{name: "other secret", key: "sk-" + strings.Repeat("s", 48), wantLogged: "key_length=51"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			service := auth.New(slog.New(slog.NewTextHandler(&buf, nil)), memory.New())
+
+			if _, err := service.Authenticate(context.Background(), tt.key); !errors.Is(err, auth.ErrInvalidKey) {
+				t.Fatalf("Authenticate() error = %v, want %v", err, auth.ErrInvalidKey)
+			}
+
+			logged := buf.String()
+
+			if !strings.Contains(logged, tt.wantLogged) {
+				t.Errorf("log = %q, want it to contain %q", logged, tt.wantLogged)
+			}
+
+			if tt.key != unknown && strings.Contains(logged, tt.key) {
+				t.Errorf("log = %q contains the key", logged)
+			}
+
+			if strings.Contains(logged, unknown[apikey.PrefixLen:]) {
+				t.Errorf("log = %q contains the secret part of the key", logged)
+			}
+		})
+	}
+}
@@ -0,0 +1,177 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"maps"
+	"sync"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/sl"
+)
+
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Probe checks one dependency. The service isn't ready while a required
+// probe fails, optional ones only make it degraded.
+type Probe struct {
+	Name     string
+	Pinger   Pinger
+	Required bool
+}
+
+// HealthService probes the dependencies in the background and keeps the
+// latest results, so readiness checks never wait for a dependency.
+type HealthService struct {
+	log      *slog.Logger
+	probes   []Probe
+	interval time.Duration
+	timeout  time.Duration
+
+	mu      sync.RWMutex
+	results map[string]models.ComponentHealth
+}
+
+func New(
+	log *slog.Logger,
+	interval time.Duration,
+	timeout time.Duration,
+	probes ...Probe,
+) *HealthService {
+	results := make(map[string]models.ComponentHealth, len(probes))
+	for _, probe := range probes {
+		results[probe.Name] = models.ComponentHealth{
+			Status:   models.HealthUnknown,
+			Required: probe.Required,
+		}
+	}
+
+	return &HealthService{
+		log:      log,
+		probes:   probes,
+		interval: interval,
+		timeout:  timeout,
+		results:  results,
+	}
+}
+
+// Run probes every dependency right away and then on every interval until
+// ctx is cancelled.
+func (s *HealthService) Run(ctx context.Context) {
+	const op = "service.health.Run"
+
+	log := s.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.checkAll(ctx, log)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Readiness returns the results of the last probes.
+func (s *HealthService) Readiness() *models.Readiness {
+	s.mu.RLock()
+	components := maps.Clone(s.results)
+	s.mu.RUnlock()
+
+	readiness := &models.Readiness{
+		Status:     models.ReadinessOK,
+		Components: components,
+	}
+
+	for _, component := range components {
+		if component.Status == models.HealthUp {
+			continue
+		}
+
+		if component.Required {
+			readiness.Status = models.ReadinessUnavailable
+
+			break
+		}
+
+		readiness.Status = models.ReadinessDegraded
+	}
+
+	return readiness
+}
+
+func (s *HealthService) checkAll(ctx context.Context, log *slog.Logger) {
+	var wg sync.WaitGroup
+
+	for _, probe := range s.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s.check(ctx, log, probe)
+		}()
+	}
+
+	wg.Wait()
+}
+
+func (s *HealthService) check(ctx context.Context, log *slog.Logger, probe Probe) {
+	probeCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	err := probe.Pinger.Ping(probeCtx)
+	checkedAt := time.Now().UTC()
+
+	result := models.ComponentHealth{
+		Status:    models.HealthUp,
+		Required:  probe.Required,
+		LatencyMS: time.Since(start).Milliseconds(),
+		CheckedAt: &checkedAt,
+	}
+
+	if err != nil {
+		if ctx.Err() != nil {
+			// Shutting down, keep the last result.
+			return
+		}
+
+		result.Status = models.HealthDown
+		result.Error = publicError(err)
+	}
+
+	s.mu.Lock()
+	previous := s.results[probe.Name].Status
+	s.results[probe.Name] = result
+	s.mu.Unlock()
+
+	if previous == result.Status {
+		return
+	}
+
+	log = log.With(slog.String("component", probe.Name))
+
+	if err != nil {
+		log.ErrorContext(ctx, "health check failed", sl.Err(err))
+	} else {
+		log.InfoContext(ctx, "component is up")
+	}
+}
+
+// publicError is the reason shown on the unauthenticated readiness
+// endpoint. Raw errors may name hosts and addresses, they are only logged.
+func publicError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return "timeout"
+	}
+
+	return "unreachable"
+}
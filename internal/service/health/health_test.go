@@ -0,0 +1,101 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+)
+
+type pingFunc func(ctx context.Context) error
+
+func (f pingFunc) Ping(ctx context.Context) error {
+	return f(ctx)
+}
+
+func up(context.Context) error {
+	return nil
+}
+
+func down(context.Context) error {
+	return errors.New("dial tcp 10.0.0.5:5432: connect: connection refused")
+}
+
+func hang(ctx context.Context) error {
+	<-ctx.Done()
+
+	return fmt.Errorf("redis 10.0.0.6:6379: %w", ctx.Err())
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		probes     []Probe
+		want       models.ReadinessStatus
+		wantErrors map[string]string
+	}{
+		{
+			name: "everything up",
+			probes: []Probe{
+				{Name: "postgres", Pinger: pingFunc(up), Required: true},
+				{Name: "redis", Pinger: pingFunc(up)},
+			},
+			want: models.ReadinessOK,
+		},
+		{
+			name: "optional component down",
+			probes: []Probe{
+				{Name: "postgres", Pinger: pingFunc(up), Required: true},
+				{Name: "redis", Pinger: pingFunc(hang)},
+			},
+			want:       models.ReadinessDegraded,
+			wantErrors: map[string]string{"redis": "timeout"},
+		},
+		{
+			name: "required component down",
+			probes: []Probe{
+				{Name: "postgres", Pinger: pingFunc(down), Required: true},
+				{Name: "redis", Pinger: pingFunc(up)},
+			},
+			want:       models.ReadinessUnavailable,
+			wantErrors: map[string]string{"postgres": "unreachable"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := New(slogdiscard.NewDiscardLogger(), time.Hour, 10*time.Millisecond, tt.probes...)
+
+			s.checkAll(context.Background(), s.log)
+
+			readiness := s.Readiness()
+			if readiness.Status != tt.want {
+				t.Errorf("status = %q, want %q", readiness.Status, tt.want)
+			}
+
+			for name, component := range readiness.Components {
+				if component.Error != tt.wantErrors[name] {
+					t.Errorf("%s error = %q, want %q", name, component.Error, tt.wantErrors[name])
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessBeforeFirstCheck(t *testing.T) {
+	s := New(slogdiscard.NewDiscardLogger(), time.Hour, time.Second,
+		Probe{Name: "postgres", Pinger: pingFunc(up), Required: true})
+
+	readiness := s.Readiness()
+	if readiness.Status != models.ReadinessUnavailable {
+		t.Errorf("status = %q, want %q", readiness.Status, models.ReadinessUnavailable)
+	}
+
+	if got := readiness.Components["postgres"].Status; got != models.HealthUnknown {
+		t.Errorf("postgres status = %q, want %q", got, models.HealthUnknown)
+	}
+}
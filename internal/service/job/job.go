@@ -0,0 +1,369 @@
+package job
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
+	trackService "lyrics-library/internal/service/track"
+	"lyrics-library/internal/storage"
+)
+
+type JobStorage interface {
+	CreateJob(ctx context.Context, job *models.Job) error
+	Job(ctx context.Context, id string) (*models.Job, error)
+	ClaimJob(ctx context.Context, retryDelay time.Duration) (*models.Job, error)
+	UpdateJob(ctx context.Context, job *models.Job) error
+	RequeueStaleJobs(ctx context.Context, olderThan time.Duration) (int64, error)
+	DeleteFinishedJobs(ctx context.Context, olderThan time.Duration) (int64, error)
+}
+
+type TrackSaver interface {
+	Save(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error)
+}
+
+var (
+	ErrJobNotFound = errors.New("job not found")
+)
+
+const (
+	updateTimeout = 5 * time.Second
+
+	errTooManyAttempts = "job exceeded the maximum number of attempts"
+)
+
+type JobService struct {
+	log          *slog.Logger
+	jobStorage   JobStorage
+	trackSaver   TrackSaver
+	workers      int
+	pollInterval time.Duration
+	jobTimeout   time.Duration
+	maxAttempts  int
+	retryDelay   time.Duration
+	retention    time.Duration
+	wake         chan struct{}
+}
+
+func New(
+	log *slog.Logger,
+	jobStorage JobStorage,
+	trackSaver TrackSaver,
+	workers int,
+	pollInterval time.Duration,
+	jobTimeout time.Duration,
+	maxAttempts int,
+	retryDelay time.Duration,
+	retention time.Duration,
+) *JobService {
+	return &JobService{
+		log:          log,
+		jobStorage:   jobStorage,
+		trackSaver:   trackSaver,
+		workers:      workers,
+		pollInterval: pollInterval,
+		jobTimeout:   jobTimeout,
+		maxAttempts:  maxAttempts,
+		retryDelay:   retryDelay,
+		retention:    retention,
+		wake:         make(chan struct{}, 1),
+	}
+}
+
+func (s *JobService) Enqueue(
+	ctx context.Context,
+	artist, title string,
+	targetLangs []string,
+) (*models.Job, error) {
+	const op = "service.job.Enqueue"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op))
+
+	job := &models.Job{
+		ClientID:    clientid.FromContext(ctx),
+		Artist:      artist,
+		Title:       title,
+		TargetLangs: targetLangs,
+	}
+
+	if err := s.jobStorage.CreateJob(ctx, job); err != nil {
+		log.ErrorContext(ctx, "failed to create job", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.notify()
+
+	log.InfoContext(ctx, "job enqueued", slog.String("job_id", job.ID))
+
+	return job, nil
+}
+
+func (s *JobService) Job(ctx context.Context, id string) (*models.Job, error) {
+	const op = "service.job.Job"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op), slog.String("job_id", id))
+
+	job, err := s.jobStorage.Job(ctx, id)
+	if err != nil {
+		if errors.Is(err, storage.ErrJobNotFound) {
+			log.ErrorContext(ctx, "job not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrJobNotFound)
+		}
+
+		log.ErrorContext(ctx, "failed to get job", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// Jobs of other clients are not found, so their ids leak nothing.
+	if job.ClientID != clientid.FromContext(ctx) {
+		log.ErrorContext(ctx, "job belongs to another client")
+
+		return nil, fmt.Errorf("%s: %w", op, ErrJobNotFound)
+	}
+
+	return job, nil
+}
+
+// Run starts the workers and blocks until ctx is cancelled and every
+// worker has finished its current job. Jobs left running by a worker that
+// died, here or in another instance, are requeued and finished jobs older
+// than the retention are deleted every job timeout.
+func (s *JobService) Run(ctx context.Context) {
+	const op = "service.job.Run"
+
+	log := s.log.With(slog.String("op", op))
+
+	s.requeueStale(ctx, log)
+	s.deleteFinished(ctx, log)
+
+	log.InfoContext(ctx, "starting job workers", slog.Int("workers", s.workers))
+
+	var wg sync.WaitGroup
+
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+
+		ticker := time.NewTicker(s.jobTimeout)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.requeueStale(ctx, log)
+				s.deleteFinished(ctx, log)
+			}
+		}
+	}()
+
+	for i := 0; i < s.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			s.work(ctx, log.With(slog.Int("worker", i)))
+		}()
+	}
+
+	wg.Wait()
+
+	log.InfoContext(ctx, "job workers stopped")
+}
+
+// requeueStale requeues jobs that have been running for twice the job
+// timeout, their worker can't be running them anymore.
+func (s *JobService) requeueStale(ctx context.Context, log *slog.Logger) {
+	requeued, err := s.jobStorage.RequeueStaleJobs(ctx, 2*s.jobTimeout)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.ErrorContext(ctx, "failed to requeue stale jobs", sl.Err(err))
+		}
+
+		return
+	}
+
+	if requeued > 0 {
+		s.notify()
+
+		log.InfoContext(ctx, "stale jobs requeued", slog.Int64("count", requeued))
+	}
+}
+
+// deleteFinished deletes succeeded and failed jobs once their retention is
+// over, zero retention keeps them forever.
+func (s *JobService) deleteFinished(ctx context.Context, log *slog.Logger) {
+	if s.retention == 0 {
+		return
+	}
+
+	deleted, err := s.jobStorage.DeleteFinishedJobs(ctx, s.retention)
+	if err != nil {
+		if ctx.Err() == nil {
+			log.ErrorContext(ctx, "failed to delete finished jobs", sl.Err(err))
+		}
+
+		return
+	}
+
+	if deleted > 0 {
+		log.InfoContext(ctx, "finished jobs deleted", slog.Int64("count", deleted))
+	}
+}
+
+func (s *JobService) notify() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (s *JobService) work(ctx context.Context, log *slog.Logger) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		for s.processNext(ctx, log) {
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.wake:
+		case <-ticker.C:
+		}
+	}
+}
+
+// processNext runs one pending job and reports whether there was one.
+func (s *JobService) processNext(ctx context.Context, log *slog.Logger) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+
+	job, err := s.jobStorage.ClaimJob(ctx, s.retryDelay)
+	if err != nil {
+		if !errors.Is(err, storage.ErrNoPendingJobs) && ctx.Err() == nil {
+			log.ErrorContext(ctx, "failed to claim job", sl.Err(err))
+		}
+
+		return false
+	}
+
+	log = log.With(slog.String("job_id", job.ID))
+
+	// Every job gets its own trace instead of one spanning the worker's life.
+	ctx, span := tracing.Start(ctx, "service.job.Process", attribute.String("job.id", job.ID))
+	defer span.End()
+
+	// Attempts are counted on claim, so a job that keeps killing its worker
+	// is failed instead of being requeued forever.
+	if job.Attempts > s.maxAttempts {
+		log.ErrorContext(ctx, "job exceeded max attempts", slog.Int("attempts", job.Attempts))
+
+		job.Status = models.JobFailed
+		job.Error = errTooManyAttempts
+
+		s.updateJob(ctx, log, job)
+
+		return true
+	}
+
+	log.InfoContext(ctx, "running job", slog.Int("attempt", job.Attempts))
+
+	// The job's translations are charged to the client that enqueued it.
+	jobCtx, cancel := context.WithTimeout(clientid.WithContext(ctx, job.ClientID), s.jobTimeout)
+	defer cancel()
+
+	track, _, err := s.trackSaver.Save(jobCtx, job.Artist, job.Title, job.TargetLangs)
+
+	switch {
+	case err == nil:
+		job.Status = models.JobSucceeded
+		job.TrackUUID = track.UUID
+		job.Error = ""
+	case ctx.Err() != nil:
+		log.InfoContext(ctx, "job interrupted by shutdown, requeueing")
+
+		job.Status = models.JobPending
+	case !permanent(err) && job.Attempts < s.maxAttempts:
+		log.WarnContext(ctx, "job attempt failed, retrying", sl.Err(err),
+			slog.Int("attempt", job.Attempts), slog.Duration("retry_delay", s.retryDelay))
+
+		job.Status = models.JobPending
+		job.Error = publicError(err)
+	default:
+		log.ErrorContext(ctx, "job failed", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		job.Status = models.JobFailed
+		job.Error = publicError(err)
+	}
+
+	s.updateJob(ctx, log, job)
+
+	return true
+}
+
+// updateJob stores the outcome of the job even when ctx is cancelled by a
+// shutdown.
+func (s *JobService) updateJob(ctx context.Context, log *slog.Logger, job *models.Job) {
+	updateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), updateTimeout)
+	defer cancel()
+
+	if err := s.jobStorage.UpdateJob(updateCtx, job); err != nil {
+		log.ErrorContext(ctx, "failed to update job", sl.Err(err))
+	}
+
+	log.InfoContext(ctx, "job finished", slog.String("status", string(job.Status)))
+}
+
+// permanent reports whether err would fail the job again on retry. Other
+// errors, e.g. an unavailable upstream or database, may be transient.
+func permanent(err error) bool {
+	return errors.Is(err, trackService.ErrLyricsNotFound) ||
+		errors.Is(err, trackService.ErrFailedTranslateLyrics) ||
+		errors.Is(err, trackService.ErrQuotaExceeded)
+}
+
+// publicError hides internal failure details, the full error is logged.
+func publicError(err error) string {
+	switch {
+	case errors.Is(err, trackService.ErrLyricsNotFound):
+		return trackService.ErrLyricsNotFound.Error()
+	case errors.Is(err, trackService.ErrFailedTranslateLyrics):
+		return trackService.ErrFailedTranslateLyrics.Error()
+	case errors.Is(err, trackService.ErrQuotaExceeded):
+		return trackService.ErrQuotaExceeded.Error()
+	case errors.Is(err, trackService.ErrUpstreamUnavailable):
+		return trackService.ErrUpstreamUnavailable.Error()
+	case errors.Is(err, context.DeadlineExceeded):
+		return "job timed out"
+	default:
+		return "internal error"
+	}
+}
@@ -0,0 +1,281 @@
+package job_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/clientid"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/service/job"
+	"lyrics-library/internal/service/track"
+	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/memory"
+)
+
+const trackUUID = "6f1c1d0e-7f4a-4c59-8f7e-2d6c3f7f0a11"
+
+type saverFunc func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error)
+
+func (f saverFunc) Save(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+	return f(ctx, artist, title, targetLangs)
+}
+
+// run starts the service and stops it when the test ends.
+func run(t *testing.T, service *job.JobService) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+
+		service.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitForStatus(t *testing.T, storage *memory.Storage, id string, status models.JobStatus) *models.Job {
+	t.Helper()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		got, err := storage.Job(context.Background(), id)
+		if err != nil {
+			t.Fatalf("Job() error = %v", err)
+		}
+
+		if got.Status == status {
+			return got
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatalf("job status = %q, want %q", got.Status, status)
+		}
+
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestJobFailsAfterMaxAttempts(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	var saves atomic.Int32
+	saver := saverFunc(func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+		saves.Add(1)
+
+		return &models.Track{UUID: trackUUID}, true, nil
+	})
+
+	j := &models.Job{Artist: "artist", Title: "title"}
+	if err := storage.CreateJob(ctx, j); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	// Two workers died while running the job.
+	for range 2 {
+		if _, err := storage.ClaimJob(ctx, 0); err != nil {
+			t.Fatalf("ClaimJob() error = %v", err)
+		}
+
+		if _, err := storage.RequeueStaleJobs(ctx, -time.Second); err != nil {
+			t.Fatalf("RequeueStaleJobs() error = %v", err)
+		}
+	}
+
+	run(t, job.New(slogdiscard.NewDiscardLogger(), storage, saver, 1, 10*time.Millisecond, time.Minute, 2, 0, 0))
+
+	got := waitForStatus(t, storage, j.ID, models.JobFailed)
+
+	if got.Attempts != 3 {
+		t.Errorf("attempts = %d, want 3", got.Attempts)
+	}
+
+	if got.Error == "" {
+		t.Error("failed job has no error")
+	}
+
+	if saves.Load() != 0 {
+		t.Errorf("Save called %d times, want 0", saves.Load())
+	}
+}
+
+func TestTransientErrorsAreRetried(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	var saves atomic.Int32
+	saver := saverFunc(func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+		if saves.Add(1) == 1 {
+			return nil, false, track.ErrUpstreamUnavailable
+		}
+
+		return &models.Track{UUID: trackUUID}, true, nil
+	})
+
+	j := &models.Job{Artist: "artist", Title: "title"}
+	if err := storage.CreateJob(ctx, j); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	run(t, job.New(slogdiscard.NewDiscardLogger(), storage, saver, 1, 10*time.Millisecond, time.Minute, 3, 20*time.Millisecond, 0))
+
+	got := waitForStatus(t, storage, j.ID, models.JobSucceeded)
+
+	if got.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", got.Attempts)
+	}
+
+	if got.Error != "" {
+		t.Errorf("error = %q, want none after a successful retry", got.Error)
+	}
+}
+
+func TestJobFailsOnError(t *testing.T) {
+	tests := []struct {
+		name         string
+		err          error
+		wantAttempts int
+	}{
+		{name: "permanent", err: track.ErrLyricsNotFound, wantAttempts: 1},
+		{name: "transient", err: track.ErrUpstreamUnavailable, wantAttempts: 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			storage := memory.New()
+
+			var saves atomic.Int32
+			saver := saverFunc(func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+				saves.Add(1)
+
+				return nil, false, tt.err
+			})
+
+			j := &models.Job{Artist: "artist", Title: "title"}
+			if err := storage.CreateJob(ctx, j); err != nil {
+				t.Fatalf("CreateJob() error = %v", err)
+			}
+
+			run(t, job.New(slogdiscard.NewDiscardLogger(), storage, saver, 1, 5*time.Millisecond, time.Minute, 3, 0, 0))
+
+			got := waitForStatus(t, storage, j.ID, models.JobFailed)
+
+			if got.Attempts != tt.wantAttempts {
+				t.Errorf("attempts = %d, want %d", got.Attempts, tt.wantAttempts)
+			}
+
+			if got.Error != tt.err.Error() {
+				t.Errorf("error = %q, want %q", got.Error, tt.err.Error())
+			}
+
+			if saves.Load() != int32(tt.wantAttempts) {
+				t.Errorf("Save called %d times, want %d", saves.Load(), tt.wantAttempts)
+			}
+		})
+	}
+}
+
+func TestStaleJobsAreRequeuedWhileRunning(t *testing.T) {
+	ctx := context.Background()
+	storage := memory.New()
+
+	saver := saverFunc(func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+		return &models.Track{UUID: trackUUID}, true, nil
+	})
+
+	// The job is claimed by a worker of another instance that dies.
+	j := &models.Job{Artist: "artist", Title: "title"}
+	if err := storage.CreateJob(ctx, j); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	if _, err := storage.ClaimJob(ctx, 0); err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+
+	// The job isn't stale yet when the service starts, only the periodic
+	// check can requeue it.
+	run(t, job.New(slogdiscard.NewDiscardLogger(), storage, saver, 1, 10*time.Millisecond, 20*time.Millisecond, 3, 0, 0))
+
+	got := waitForStatus(t, storage, j.ID, models.JobSucceeded)
+
+	if got.TrackUUID != trackUUID {
+		t.Errorf("track uuid = %q, want %q", got.TrackUUID, trackUUID)
+	}
+
+	if got.Attempts != 2 {
+		t.Errorf("attempts = %d, want 2", got.Attempts)
+	}
+}
+
+func TestFinishedJobsAreDeletedAfterRetention(t *testing.T) {
+	ctx := context.Background()
+	jobStorage := memory.New()
+
+	saver := saverFunc(func(ctx context.Context, artist, title string, targetLangs []string) (*models.Track, bool, error) {
+		return &models.Track{UUID: trackUUID}, true, nil
+	})
+
+	finished := &models.Job{Artist: "artist", Title: "finished"}
+	if err := jobStorage.CreateJob(ctx, finished); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	if _, err := jobStorage.ClaimJob(ctx, 0); err != nil {
+		t.Fatalf("ClaimJob() error = %v", err)
+	}
+
+	finished.Status = models.JobFailed
+	if err := jobStorage.UpdateJob(ctx, finished); err != nil {
+		t.Fatalf("UpdateJob() error = %v", err)
+	}
+
+	pending := &models.Job{Artist: "artist", Title: "pending"}
+	if err := jobStorage.CreateJob(ctx, pending); err != nil {
+		t.Fatalf("CreateJob() error = %v", err)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	run(t, job.New(slogdiscard.NewDiscardLogger(), jobStorage, saver, 1, 10*time.Millisecond, time.Minute, 3, 0, 10*time.Millisecond))
+
+	// Finished jobs are deleted before the workers start.
+	waitForStatus(t, jobStorage, pending.ID, models.JobSucceeded)
+
+	if _, err := jobStorage.Job(ctx, finished.ID); !errors.Is(err, storage.ErrJobNotFound) {
+		t.Errorf("Job() error = %v for a job past its retention, want %v", err, storage.ErrJobNotFound)
+	}
+}
+
+func TestJobOfAnotherClientIsNotFound(t *testing.T) {
+	storage := memory.New()
+	service := job.New(slogdiscard.NewDiscardLogger(), storage, nil, 1, time.Second, time.Minute, 3, 0, 0)
+
+	owner := clientid.WithContext(context.Background(), "key:owner")
+
+	created, err := service.Enqueue(owner, "artist", "title", nil)
+	if err != nil {
+		t.Fatalf("Enqueue() error = %v", err)
+	}
+
+	if _, err := service.Job(owner, created.ID); err != nil {
+		t.Errorf("Job() error = %v for the client that created the job", err)
+	}
+
+	other := clientid.WithContext(context.Background(), "key:other")
+
+	if _, err := service.Job(other, created.ID); !errors.Is(err, job.ErrJobNotFound) {
+		t.Errorf("Job() error = %v for another client, want %v", err, job.ErrJobNotFound)
+	}
+}
@@ -5,49 +5,104 @@ import (
 	"errors"
 	"fmt"
 	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"time"
 
 	"lyrics-library/internal/client"
 	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/background"
 	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/lib/tracing"
 	"lyrics-library/internal/storage"
 )
 
 type LyricsProvider interface {
-	Lyrics(ctx context.Context, artist, title string) ([]string, error)
+	Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error)
 }
 
 type LyricsTranslator interface {
-	TranslateLyrics(ctx context.Context, lyrics []string) ([]string, error)
+	TranslateLyrics(ctx context.Context, lyrics []string, targetLang string) ([]string, error)
 }
 
 type TrackStorage interface {
-	SaveTrack(ctx context.Context, track *models.Track) error
+	SaveTrack(ctx context.Context, track *models.Track) (bool, error)
 	Track(ctx context.Context, artist, title string) (*models.Track, error)
-	TracksByArtist(ctx context.Context, artist string) ([]*models.Track, error)
-	DeleteTrack(ctx context.Context, uuid string) error
+	TrackByUUID(ctx context.Context, uuid string) (*models.Track, error)
+	SaveTranslation(ctx context.Context, uuid, lang string, lines []string) error
+	SaveSyncedLyrics(ctx context.Context, uuid string, lines []models.SyncedLine) error
+	UpdateTrack(ctx context.Context, track *models.Track, version int64, langs []string) (*models.Track, error)
+	TracksByArtist(ctx context.Context, artist string, page models.PageQuery) (*models.TrackPage, error)
+	DeleteTrack(ctx context.Context, uuid string) (*models.Track, error)
+	SearchTracks(ctx context.Context, query models.SearchQuery) ([]*models.SearchHit, error)
 }
 
+// TrackCache is filled with what is read from the storage and written
+// through when the storage changes. A fill carries the Generation taken
+// before the storage read and is dropped if the cache has been written or
+// invalidated since, so a slow fill never overwrites a newer change.
 type TrackCache interface {
-	SaveArtistTracks(ctx context.Context, artist string, tracks []*models.Track) error
-	ArtistTracks(ctx context.Context, artist string) ([]*models.Track, error)
+	Generation(ctx context.Context) (int64, error)
+	SaveArtistTracks(ctx context.Context, artist string, query models.PageQuery, page *models.TrackPage, generation int64) error
+	ArtistTracks(ctx context.Context, artist string, query models.PageQuery) (*models.TrackPage, error)
 	Track(ctx context.Context, artist, title string) (*models.Track, error)
+	TrackByUUID(ctx context.Context, uuid string) (*models.Track, error)
 	SaveTrack(ctx context.Context, track *models.Track) error
+	FillTrack(ctx context.Context, track *models.Track, generation int64) error
+	DeleteTrack(ctx context.Context, track *models.Track) error
+	DeleteArtistTracks(ctx context.Context, artist string) error
 }
 
 var (
 	ErrLyricsNotFound        = errors.New("lyrics not found")
 	ErrFailedTranslateLyrics = errors.New("failed to translate lyrics")
 	ErrTrackNotFound         = errors.New("track not found")
+	ErrTrackExists           = errors.New("track already exists")
+	ErrVersionMismatch       = errors.New("track has been changed since it was read")
+	ErrInvalidUpdate         = errors.New("invalid update")
 	ErrArtistTracksNotFound  = errors.New("artist's tracks not found")
-	ErrInvalidUUID           = errors.New("invalid uuid")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrQuotaExceeded         = errors.New("daily translation quota exceeded")
+	ErrUpstreamUnavailable   = errors.New("upstream api is unavailable")
 )
 
+// QuotaExceededError tells when the translation quota of the client is
+// renewed. It matches ErrQuotaExceeded with errors.Is.
+type QuotaExceededError struct {
+	RetryAfter time.Duration
+}
+
+func (e *QuotaExceededError) Error() string {
+	return ErrQuotaExceeded.Error()
+}
+
+func (e *QuotaExceededError) Unwrap() error {
+	return ErrQuotaExceeded
+}
+
+// InvalidUpdateError tells why an update cannot be applied. It matches
+// ErrInvalidUpdate with errors.Is.
+type InvalidUpdateError struct {
+	Reason string
+}
+
+func (e *InvalidUpdateError) Error() string {
+	return e.Reason
+}
+
+func (e *InvalidUpdateError) Unwrap() error {
+	return ErrInvalidUpdate
+}
+
 type TrackService struct {
 	log              *slog.Logger
 	lyricsProvider   LyricsProvider
 	lyricsTranslator LyricsTranslator
 	trackStorage     TrackStorage
 	trackCache       TrackCache
+	defaultLangs     []string
+	background       *background.Group
 }
 
 func New(
@@ -56,6 +111,8 @@ func New(
 	lyricsTranslator LyricsTranslator,
 	trackStorage TrackStorage,
 	trackCache TrackCache,
+	defaultLangs []string,
+	background *background.Group,
 ) *TrackService {
 	return &TrackService{
 		log:              log,
@@ -63,77 +120,229 @@ func New(
 		lyricsTranslator: lyricsTranslator,
 		trackStorage:     trackStorage,
 		trackCache:       trackCache,
+		defaultLangs:     defaultLangs,
+		background:       background,
 	}
 }
 
+// Save fetches, translates into targetLangs and stores the track. If the
+// track is already stored, the existing one is returned with the missing
+// translations added and created is false. Empty targetLangs means the
+// default languages.
 func (s *TrackService) Save(
 	ctx context.Context,
 	artist, title string,
-) (*models.Track, error) {
+	targetLangs []string,
+) (*models.Track, bool, error) {
 	const op = "service.track.Save"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	log := s.log.With("op", op)
 
-	log.Info("saving track")
+	log.InfoContext(ctx, "saving track")
 
-	cached, err := s.trackCache.Track(ctx, artist, title)
-	if err == nil {
-		log.Info("returnig cached track")
+	if len(targetLangs) == 0 {
+		targetLangs = s.defaultLangs
+	}
 
-		return cached, nil
+	existing, err := s.trackCache.Track(ctx, artist, title)
+	cached := err == nil
+
+	var (
+		generation int64
+		fillable   bool
+	)
+
+	if !cached {
+		generation, fillable = s.cacheGeneration(ctx)
+
+		existing, err = s.trackStorage.Track(ctx, artist, title)
+		if err != nil && !errors.Is(err, storage.ErrTrackNotFound) {
+			log.ErrorContext(ctx, "failed to get track", sl.Err(err))
+
+			tracing.Fail(span, err)
+
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	if existing != nil {
+		log.InfoContext(ctx, "track already exists",
+			slog.String("uuid", existing.UUID),
+			slog.Bool("cached", cached),
+		)
+
+		added, err := s.addTranslations(ctx, log, existing, targetLangs)
+		if err != nil {
+			tracing.Fail(span, err)
+
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		switch {
+		case added:
+			s.writeThrough(ctx, log, existing)
+		case !cached && fillable:
+			s.cacheTrack(ctx, log, existing, generation)
+		}
+
+		return existing, false, nil
 	}
 
 	lyrics, err := s.lyricsProvider.Lyrics(ctx, artist, title)
 	if err != nil {
 		if errors.Is(err, client.ErrLyricsNotFound) {
-			log.Error("lyrics not found", sl.Err(err))
+			log.ErrorContext(ctx, "lyrics not found", sl.Err(err))
 
-			return nil, fmt.Errorf("%s: %w", op, ErrLyricsNotFound)
+			return nil, false, fmt.Errorf("%s: %w", op, ErrLyricsNotFound)
 		}
 
-		log.Error("failed to fetch lyrics", sl.Err(err))
+		log.ErrorContext(ctx, "failed to fetch lyrics", sl.Err(err))
 
-		return nil, fmt.Errorf("%s: %w", op, err)
+		tracing.Fail(span, err)
+
+		if ctx.Err() != nil {
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		return nil, false, fmt.Errorf("%s: %w: %w", op, ErrUpstreamUnavailable, err)
 	}
 
-	log.Debug("lyrics fetched", slog.Any("lyrics", lyrics))
+	log.DebugContext(ctx, "lyrics fetched", slog.String("source", lyrics.Source), slog.Any("lyrics", lyrics.Lines))
+
+	translations := make(map[string][]string, len(targetLangs))
+	for _, lang := range targetLangs {
+		translation, err := s.translate(ctx, log, lyrics.Lines, lang)
+		if err != nil {
+			tracing.Fail(span, err)
+
+			return nil, false, fmt.Errorf("%s: %w", op, err)
+		}
+
+		translations[lang] = translation
+	}
 
-	translation, err := s.lyricsTranslator.TranslateLyrics(ctx, lyrics)
+	track := &models.Track{
+		Artist:       artist,
+		Title:        title,
+		Lyrics:       lyrics.Lines,
+		Translations: translations,
+		Source:       lyrics.Source,
+	}
+
+	created, err := s.trackStorage.SaveTrack(ctx, track)
 	if err != nil {
-		log.Error("failed translate lyrics", sl.Err(err))
+		log.ErrorContext(ctx, "failed to save track", sl.Err(err))
 
-		if errors.Is(err, client.ErrFailedTranslateLyrics) {
+		tracing.Fail(span, err)
+
+		return nil, false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if !created {
+		log.InfoContext(ctx, "track was saved concurrently", slog.String("uuid", track.UUID))
+
+		if _, err := s.addTranslations(ctx, log, track, targetLangs); err != nil {
+			tracing.Fail(span, err)
 
-			return nil, fmt.Errorf("%s: %w", op, ErrFailedTranslateLyrics)
+			return nil, false, fmt.Errorf("%s: %w", op, err)
 		}
+	}
 
-		return nil, fmt.Errorf("%s: %w", op, err)
+	s.writeThrough(ctx, log, track)
+
+	log.InfoContext(ctx, "lyrics saved successfully", slog.Bool("created", created))
+
+	return track, created, nil
+}
+
+// addTranslations translates an already stored track into the languages
+// it is missing. added reports whether the track has been changed.
+func (s *TrackService) addTranslations(
+	ctx context.Context,
+	log *slog.Logger,
+	track *models.Track,
+	langs []string,
+) (bool, error) {
+	if track.Translations == nil {
+		track.Translations = make(map[string][]string, len(langs))
 	}
 
-	track := &models.Track{
-		Artist:      artist,
-		Title:       title,
-		Lyrics:      lyrics,
-		Translation: translation,
+	var added bool
+	for _, lang := range langs {
+		if _, ok := track.Translations[lang]; ok {
+			continue
+		}
+
+		translation, err := s.translate(ctx, log, track.Lyrics, lang)
+		if err != nil {
+			return added, err
+		}
+
+		if err := s.trackStorage.SaveTranslation(ctx, track.UUID, lang, translation); err != nil {
+			log.ErrorContext(ctx, "failed to save translation", sl.Err(err))
+
+			return added, err
+		}
+
+		track.Translations[lang] = translation
+		added = true
 	}
 
-	if err := s.trackStorage.SaveTrack(ctx, track); err != nil {
-		log.Error("failed to save track", sl.Err(err))
+	if added {
+		// Every saved translation bumps the stored version.
+		refreshed, err := s.trackStorage.TrackByUUID(ctx, track.UUID)
+		if err != nil {
+			log.ErrorContext(ctx, "failed to get track", sl.Err(err))
 
-		return nil, fmt.Errorf("%s: %w", op, err)
+			return added, err
+		}
+
+		*track = *refreshed
 	}
 
-	go func() {
-		log.Info("saving track in cache")
+	return added, nil
+}
 
-		if err := s.trackCache.SaveTrack(ctx, track); err != nil {
-			log.Error("failed to cache track", sl.Err(err))
+func (s *TrackService) translate(
+	ctx context.Context,
+	log *slog.Logger,
+	lyrics []string,
+	lang string,
+) ([]string, error) {
+	translation, err := s.lyricsTranslator.TranslateLyrics(ctx, lyrics, lang)
+	if err != nil {
+		log.ErrorContext(ctx, "failed translate lyrics", slog.String("lang", lang), sl.Err(err))
+
+		if errors.Is(err, client.ErrFailedTranslateLyrics) {
+			return nil, ErrFailedTranslateLyrics
 		}
-	}()
 
-	log.Info("lyrics saved successfully")
+		var quotaErr *client.QuotaExceededError
+		if errors.As(err, &quotaErr) {
+			return nil, &QuotaExceededError{RetryAfter: quotaErr.RetryAfter}
+		}
 
-	return track, nil
+		if ctx.Err() != nil {
+			return nil, err
+		}
+
+		return nil, fmt.Errorf("%w: %w", ErrUpstreamUnavailable, err)
+	}
+
+	if len(translation) != len(lyrics) {
+		log.ErrorContext(ctx, "translation is not line-aligned",
+			slog.String("lang", lang),
+			slog.Int("lines", len(lyrics)),
+			slog.Int("translated_lines", len(translation)),
+		)
+
+		return nil, ErrFailedTranslateLyrics
+	}
+
+	return translation, nil
 }
 
 func (s *TrackService) Track(
@@ -142,99 +351,444 @@ func (s *TrackService) Track(
 ) (*models.Track, error) {
 	const op = "service.track.Track"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	log := s.log.With(slog.String("op", op))
 
-	log.Info("getting track")
+	log.InfoContext(ctx, "getting track")
 
 	cached, err := s.trackCache.Track(ctx, artist, title)
 	if err == nil {
-		log.Info("returnig cached track")
+		log.InfoContext(ctx, "returnig cached track")
 
 		return cached, nil
 	}
 
+	generation, fillable := s.cacheGeneration(ctx)
+
 	track, err := s.trackStorage.Track(ctx, artist, title)
 	if err != nil {
-		log.Error("failed to get track", sl.Err(err))
+		log.ErrorContext(ctx, "failed to get track", sl.Err(err))
 
 		if errors.Is(err, storage.ErrTrackNotFound) {
 			return nil, fmt.Errorf("%s: %w", op, ErrTrackNotFound)
 		}
 
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	go func() {
-		log.Info("caching track")
+	if fillable {
+		s.cacheTrack(ctx, log, track, generation)
+	}
+
+	log.InfoContext(ctx, "track got successfully")
+
+	return track, nil
+}
+
+func (s *TrackService) TrackByUUID(ctx context.Context, uuid string) (*models.Track, error) {
+	const op = "service.track.TrackByUUID"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op), slog.String("uuid", uuid))
 
-		if err := s.trackCache.SaveTrack(ctx, track); err != nil {
-			log.Error("failed to cache track", sl.Err(err))
+	log.InfoContext(ctx, "getting track by uuid")
+
+	cached, err := s.trackCache.TrackByUUID(ctx, uuid)
+	if err == nil {
+		log.InfoContext(ctx, "returnig cached track")
+
+		return cached, nil
+	}
+
+	generation, fillable := s.cacheGeneration(ctx)
+
+	track, err := s.trackStorage.TrackByUUID(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, storage.ErrTrackNotFound) {
+			log.ErrorContext(ctx, "track not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTrackNotFound)
+		}
+
+		log.ErrorContext(ctx, "failed to get track", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if fillable {
+		s.cacheTrack(ctx, log, track, generation)
+	}
+
+	log.InfoContext(ctx, "track got successfully")
+
+	return track, nil
+}
+
+// SaveSyncedLyrics attaches the timed lines of an LRC file to a stored
+// track and returns the updated track.
+func (s *TrackService) SaveSyncedLyrics(
+	ctx context.Context,
+	uuid string,
+	lines []models.SyncedLine,
+) (*models.Track, error) {
+	const op = "service.track.SaveSyncedLyrics"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op), slog.String("uuid", uuid))
+
+	log.InfoContext(ctx, "saving synced lyrics", slog.Int("lines", len(lines)))
+
+	if err := s.trackStorage.SaveSyncedLyrics(ctx, uuid, lines); err != nil {
+		if errors.Is(err, storage.ErrTrackNotFound) {
+			log.ErrorContext(ctx, "track not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTrackNotFound)
 		}
-	}()
 
-	log.Info("track got successfully")
+		log.ErrorContext(ctx, "failed to save synced lyrics", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	track, err := s.trackStorage.TrackByUUID(ctx, uuid)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to get track", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.writeThrough(ctx, log, track)
+
+	log.InfoContext(ctx, "synced lyrics saved successfully")
 
 	return track, nil
 }
 
-func (s *TrackService) ArtistTracks(ctx context.Context, artist string) ([]*models.Track, error) {
+// Update applies a manual correction to a stored track and returns the
+// updated track. version is the version the client has read, 0 skips the
+// check. Edited translations are marked as manual.
+func (s *TrackService) Update(
+	ctx context.Context,
+	uuid string,
+	version int64,
+	update models.TrackUpdate,
+) (*models.Track, error) {
+	const op = "service.track.Update"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op), slog.String("uuid", uuid))
+
+	log.InfoContext(ctx, "updating track", slog.Int64("version", version))
+
+	current, err := s.trackStorage.TrackByUUID(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, storage.ErrTrackNotFound) {
+			log.ErrorContext(ctx, "track not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTrackNotFound)
+		}
+
+		log.ErrorContext(ctx, "failed to get track", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if version != 0 && version != current.Version {
+		log.InfoContext(ctx, "version mismatch", slog.Int64("current_version", current.Version))
+
+		return nil, fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+	}
+
+	updated, langs, err := applyUpdate(current, update)
+	if err != nil {
+		log.InfoContext(ctx, "invalid update", sl.Err(err))
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	saved, err := s.trackStorage.UpdateTrack(ctx, updated, current.Version, langs)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrTrackNotFound):
+			log.ErrorContext(ctx, "track not found")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTrackNotFound)
+		case errors.Is(err, storage.ErrVersionConflict):
+			log.InfoContext(ctx, "track changed concurrently")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrVersionMismatch)
+		case errors.Is(err, storage.ErrTrackExists):
+			log.InfoContext(ctx, "track with the same artist and title exists")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrTrackExists)
+		}
+
+		log.ErrorContext(ctx, "failed to update track", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The artist and title may have changed, so the old entries go first.
+	s.invalidate(ctx, log, current)
+	s.writeThrough(ctx, log, saved)
+
+	log.InfoContext(ctx, "track updated successfully", slog.Int64("version", saved.Version))
+
+	return saved, nil
+}
+
+// applyUpdate returns a copy of track with update applied and the
+// languages of the edited translations.
+func applyUpdate(track *models.Track, update models.TrackUpdate) (*models.Track, []string, error) {
+	updated := *track
+
+	if update.Artist != nil {
+		updated.Artist = strings.TrimSpace(*update.Artist)
+		if updated.Artist == "" {
+			return nil, nil, &InvalidUpdateError{Reason: "artist must not be empty"}
+		}
+	}
+
+	if update.Title != nil {
+		updated.Title = strings.TrimSpace(*update.Title)
+		if updated.Title == "" {
+			return nil, nil, &InvalidUpdateError{Reason: "title must not be empty"}
+		}
+	}
+
+	if len(update.Lyrics) > 0 {
+		lines, err := editLines(track.Lyrics, update.Lyrics)
+		if err != nil {
+			return nil, nil, &InvalidUpdateError{Reason: "lyrics: " + err.Error()}
+		}
+
+		updated.Lyrics = lines
+	}
+
+	langs := slices.Sorted(maps.Keys(update.Translations))
+
+	updated.Translations = maps.Clone(track.Translations)
+
+	for _, lang := range langs {
+		translation, ok := track.Translations[lang]
+		if !ok {
+			return nil, nil, &InvalidUpdateError{Reason: "translations." + lang + ": track has no such translation"}
+		}
+
+		lines, err := editLines(translation, update.Translations[lang])
+		if err != nil {
+			return nil, nil, &InvalidUpdateError{Reason: "translations." + lang + ": " + err.Error()}
+		}
+
+		updated.Translations[lang] = lines
+	}
+
+	return &updated, langs, nil
+}
+
+func editLines(lines []string, edits []models.LineEdit) ([]string, error) {
+	edited := slices.Clone(lines)
+
+	for _, edit := range edits {
+		if edit.Line < 1 || edit.Line > len(lines) {
+			return nil, fmt.Errorf("line %d is out of range 1-%d", edit.Line, len(lines))
+		}
+
+		if strings.ContainsAny(edit.Text, "\r\n") {
+			return nil, fmt.Errorf("line %d must not contain line breaks", edit.Line)
+		}
+
+		edited[edit.Line-1] = edit.Text
+	}
+
+	return edited, nil
+}
+
+func (s *TrackService) ArtistTracks(
+	ctx context.Context,
+	artist string,
+	query models.PageQuery,
+) (*models.TrackPage, error) {
 	const op = "service.track.ArtistTracks"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	log := s.log.With(slog.String("op", op))
 
-	cached, err := s.trackCache.ArtistTracks(ctx, artist)
+	cached, err := s.trackCache.ArtistTracks(ctx, artist, query)
 	if err == nil {
-		log.Info("getting tracks from cache")
+		log.InfoContext(ctx, "getting tracks from cache")
 
 		return cached, nil
 	}
 
-	tracks, err := s.trackStorage.TracksByArtist(ctx, artist)
+	generation, fillable := s.cacheGeneration(ctx)
+
+	page, err := s.trackStorage.TracksByArtist(ctx, artist, query)
 	if err != nil {
 		if errors.Is(err, storage.ErrArtistTracksNotFound) {
-			log.Error("artist's track not found")
+			log.ErrorContext(ctx, "artist's track not found")
 
 			return nil, fmt.Errorf("%s: %w", op, ErrArtistTracksNotFound)
 		}
 
-		log.Error("failed to get tracks by artist", sl.Err(err))
+		if errors.Is(err, storage.ErrInvalidCursor) {
+			log.ErrorContext(ctx, "invalid cursor")
+
+			return nil, fmt.Errorf("%s: %w", op, ErrInvalidCursor)
+		}
+
+		log.ErrorContext(ctx, "failed to get tracks by artist", sl.Err(err))
+
+		tracing.Fail(span, err)
 
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	go func() {
-		log.Info("caching artist's tracks")
+	if fillable {
+		s.runBackground(ctx, log, func(ctx context.Context) {
+			log.InfoContext(ctx, "caching artist's tracks")
 
-		if err := s.trackCache.SaveArtistTracks(ctx, artist, tracks); err != nil {
-			log.Error("failed to cache artist tracks", sl.Err(err))
-		}
-	}()
+			if err := s.trackCache.SaveArtistTracks(ctx, artist, query, page, generation); err != nil {
+				log.ErrorContext(ctx, "failed to cache artist tracks", sl.Err(err))
+			}
+		})
+	}
 
-	log.Info("artist's tracks got successfully", slog.Any("tracks", tracks))
+	log.InfoContext(ctx, "artist's tracks got successfully", slog.Int("count", len(page.Tracks)))
 
-	return tracks, nil
+	return page, nil
+}
+
+func (s *TrackService) Search(
+	ctx context.Context,
+	query models.SearchQuery,
+) ([]*models.SearchHit, error) {
+	const op = "service.track.Search"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	log := s.log.With(slog.String("op", op))
+
+	log.InfoContext(ctx, "searching tracks", slog.String("query", query.Query))
+
+	hits, err := s.trackStorage.SearchTracks(ctx, query)
+	if err != nil {
+		log.ErrorContext(ctx, "failed to search tracks", sl.Err(err))
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	log.InfoContext(ctx, "tracks searched successfully", slog.Int("count", len(hits)))
+
+	return hits, nil
 }
 
 func (s *TrackService) Delete(ctx context.Context, uuid string) error {
 	const op = "service.track.Delete"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
 	log := s.log.With(slog.String("op", op))
 
-	log.Info("deleting track by uuid")
+	log.InfoContext(ctx, "deleting track by uuid")
 
-	if err := s.trackStorage.DeleteTrack(ctx, uuid); err != nil {
-		if errors.Is(err, storage.ErrInvalidUUID) {
-			log.Error("invalid uuid")
+	track, err := s.trackStorage.DeleteTrack(ctx, uuid)
+	if err != nil {
+		if errors.Is(err, storage.ErrTrackNotFound) {
+			log.ErrorContext(ctx, "track not found")
 
-			return fmt.Errorf("%s: %w", op, ErrInvalidUUID)
+			return fmt.Errorf("%s: %w", op, ErrTrackNotFound)
 		}
 
-		log.Error("failed to delete track", sl.Err(err))
+		log.ErrorContext(ctx, "failed to delete track", sl.Err(err))
+
+		tracing.Fail(span, err)
 
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	log.Info("track deleted successfully")
+	s.invalidate(ctx, log, track)
+
+	log.InfoContext(ctx, "track deleted successfully")
 
 	return nil
 }
+
+// cacheGeneration is taken before a storage read whose result is cached.
+// Nothing is cached when the cache can't tell its generation.
+func (s *TrackService) cacheGeneration(ctx context.Context) (int64, bool) {
+	generation, err := s.trackCache.Generation(ctx)
+
+	return generation, err == nil
+}
+
+func (s *TrackService) cacheTrack(ctx context.Context, log *slog.Logger, track *models.Track, generation int64) {
+	s.runBackground(ctx, log, func(ctx context.Context) {
+		log.InfoContext(ctx, "caching track")
+
+		if err := s.trackCache.FillTrack(ctx, track, generation); err != nil {
+			log.ErrorContext(ctx, "failed to cache track", sl.Err(err))
+		}
+	})
+}
+
+// runBackground fills the cache after the response has been sent. The task
+// outlives the request but is drained on shutdown.
+func (s *TrackService) runBackground(ctx context.Context, log *slog.Logger, task func(ctx context.Context)) {
+	if err := s.background.Go(ctx, task); err != nil {
+		log.WarnContext(ctx, "cache write skipped", sl.Err(err))
+	}
+}
+
+// writeThrough replaces the cached track after it has been changed in the
+// storage and drops the cached pages of its artist. The storage change is
+// already committed, so it is done even if the request has been cancelled.
+func (s *TrackService) writeThrough(ctx context.Context, log *slog.Logger, track *models.Track) {
+	ctx = context.WithoutCancel(ctx)
+
+	if err := s.trackCache.SaveTrack(ctx, track); err != nil {
+		log.ErrorContext(ctx, "failed to cache track", sl.Err(err))
+	}
+
+	if err := s.trackCache.DeleteArtistTracks(ctx, track.Artist); err != nil {
+		log.ErrorContext(ctx, "failed to invalidate artist's tracks", sl.Err(err))
+	}
+}
+
+func (s *TrackService) invalidate(ctx context.Context, log *slog.Logger, track *models.Track) {
+	ctx = context.WithoutCancel(ctx)
+
+	if err := s.trackCache.DeleteTrack(ctx, track); err != nil {
+		log.ErrorContext(ctx, "failed to invalidate track", sl.Err(err))
+	}
+
+	if err := s.trackCache.DeleteArtistTracks(ctx, track.Artist); err != nil {
+		log.ErrorContext(ctx, "failed to invalidate artist's tracks", sl.Err(err))
+	}
+}
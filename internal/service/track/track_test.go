@@ -0,0 +1,145 @@
+package track_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/background"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/service/track"
+	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/memory"
+)
+
+type providerFunc func(ctx context.Context, artist, title string) (*models.Lyrics, error)
+
+func (f providerFunc) Lyrics(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+	return f(ctx, artist, title)
+}
+
+type translatorFunc func(ctx context.Context, lyrics []string, targetLang string) ([]string, error)
+
+func (f translatorFunc) TranslateLyrics(ctx context.Context, lyrics []string, targetLang string) ([]string, error) {
+	return f(ctx, lyrics, targetLang)
+}
+
+// blockUntilCancelled stands for an upstream call that takes longer than
+// the client is willing to wait. It tells when it has been called.
+func blockUntilCancelled(called chan<- struct{}) func(ctx context.Context) error {
+	return func(ctx context.Context) error {
+		close(called)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(5 * time.Second):
+			return errors.New("call isn't cancelled")
+		}
+	}
+}
+
+func newService(
+	t *testing.T,
+	provider track.LyricsProvider,
+	translator track.LyricsTranslator,
+) (*track.TrackService, *memory.Storage) {
+	t.Helper()
+
+	trackStorage := memory.New()
+	bg := background.New(time.Second)
+
+	t.Cleanup(func() { _ = bg.Shutdown(context.Background()) })
+
+	service := track.New(slogdiscard.NewDiscardLogger(), provider, translator, trackStorage,
+		memory.NewCache(100, time.Minute, time.Minute, nil), []string{"de"}, bg)
+
+	return service, trackStorage
+}
+
+func TestSaveStopsWhenFetchingLyricsIsCancelled(t *testing.T) {
+	called := make(chan struct{})
+	block := blockUntilCancelled(called)
+
+	var translations atomic.Int32
+
+	service, trackStorage := newService(t,
+		providerFunc(func(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+			return nil, block(ctx)
+		}),
+		translatorFunc(func(ctx context.Context, lyrics []string, targetLang string) ([]string, error) {
+			translations.Add(1)
+
+			return lyrics, nil
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-called
+		cancel()
+	}()
+
+	_, _, err := service.Save(ctx, "artist", "title", nil)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Save() error = %v, want %v", err, context.Canceled)
+	}
+
+	if errors.Is(err, track.ErrUpstreamUnavailable) {
+		t.Errorf("Save() error = %v, a cancelled request must not blame the upstream", err)
+	}
+
+	if translations.Load() != 0 {
+		t.Errorf("translator called %d times after cancellation", translations.Load())
+	}
+
+	if _, err := trackStorage.Track(context.Background(), "artist", "title"); !errors.Is(err, storage.ErrTrackNotFound) {
+		t.Errorf("Track() error = %v, want nothing saved", err)
+	}
+}
+
+func TestSaveStopsWhenTranslationIsCancelled(t *testing.T) {
+	called := make(chan struct{})
+	block := blockUntilCancelled(called)
+
+	var translations atomic.Int32
+
+	service, trackStorage := newService(t,
+		providerFunc(func(ctx context.Context, artist, title string) (*models.Lyrics, error) {
+			return &models.Lyrics{Lines: []string{"line"}, Source: "test"}, nil
+		}),
+		translatorFunc(func(ctx context.Context, lyrics []string, targetLang string) ([]string, error) {
+			translations.Add(1)
+
+			return nil, block(ctx)
+		}),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	go func() {
+		<-called
+		cancel()
+	}()
+
+	_, _, err := service.Save(ctx, "artist", "title", []string{"de", "fr"})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("Save() error = %v, want %v", err, context.Canceled)
+	}
+
+	if errors.Is(err, track.ErrUpstreamUnavailable) {
+		t.Errorf("Save() error = %v, a cancelled request must not blame the upstream", err)
+	}
+
+	if translations.Load() != 1 {
+		t.Errorf("translator called %d times, want it to stop after the cancelled call", translations.Load())
+	}
+
+	if _, err := trackStorage.Track(context.Background(), "artist", "title"); !errors.Is(err, storage.ErrTrackNotFound) {
+		t.Errorf("Track() error = %v, want nothing saved", err)
+	}
+}
@@ -0,0 +1,43 @@
+// Package cachekey builds the keys shared by the cache backends, so that
+// Redis, the in-memory cache and the guard in front of them agree on the
+// layout.
+package cachekey
+
+import (
+	"fmt"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/identity"
+)
+
+// ArtistTracks returns the key of the hash holding the cached pages of an
+// artist's tracks.
+func ArtistTracks(artist string) string {
+	return fmt.Sprintf("artist_tracks:%s", identity.Normalize(artist))
+}
+
+// PageField returns the field of a page in the ArtistTracks hash.
+func PageField(query models.PageQuery) string {
+	return fmt.Sprintf("%s:%d:%s", query.Sort, query.Limit, query.Cursor)
+}
+
+// Track returns the key of a track looked up by artist and title.
+func Track(artist, title string) string {
+	return fmt.Sprintf("track:%s", identity.TrackKey(artist, title))
+}
+
+// TrackUUID returns the key of a track looked up by UUID.
+func TrackUUID(uuid string) string {
+	return fmt.Sprintf("track_uuid:%s", uuid)
+}
+
+// TrackKeys returns every key a track is cached under.
+func TrackKeys(track *models.Track) []string {
+	keys := []string{Track(track.Artist, track.Title)}
+
+	if track.UUID != "" {
+		keys = append(keys, TrackUUID(track.UUID))
+	}
+
+	return keys
+}
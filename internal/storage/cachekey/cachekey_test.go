@@ -0,0 +1,47 @@
+package cachekey_test
+
+import (
+	"slices"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/storage/cachekey"
+)
+
+func TestTrackKeys(t *testing.T) {
+	tests := []struct {
+		name  string
+		track *models.Track
+		want  []string
+	}{
+		{
+			name:  "normalized identity and uuid",
+			track: &models.Track{UUID: "u1", Artist: " The  Artist", Title: "TITLE"},
+			want:  []string{"track:the artist\x1ftitle", "track_uuid:u1"},
+		},
+		{
+			name:  "without uuid",
+			track: &models.Track{Artist: "artist", Title: "title"},
+			want:  []string{"track:artist\x1ftitle"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := cachekey.TrackKeys(tt.track); !slices.Equal(got, tt.want) {
+				t.Errorf("TrackKeys() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestArtistTracks(t *testing.T) {
+	if got, want := cachekey.ArtistTracks("The  ARTIST "), "artist_tracks:the artist"; got != want {
+		t.Errorf("ArtistTracks() = %q, want %q", got, want)
+	}
+
+	query := models.PageQuery{Sort: models.SortByTitle, Limit: 20, Cursor: "abc"}
+	if got, want := cachekey.PageField(query), "title:20:abc"; got != want {
+		t.Errorf("PageField() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,411 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/sl"
+	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/cachekey"
+	"lyrics-library/internal/storage/memory"
+)
+
+// maxPending limits the invalidations kept while the backend is unavailable.
+const maxPending = 10000
+
+var ErrCacheUnavailable = errors.New("cache is unavailable")
+
+// Backend is a shared cache that may go away, e.g. the Redis storage.
+type Backend interface {
+	Generation(ctx context.Context) (int64, error)
+	SaveArtistTracks(ctx context.Context, artist string, query models.PageQuery, page *models.TrackPage, generation int64) error
+	ArtistTracks(ctx context.Context, artist string, query models.PageQuery) (*models.TrackPage, error)
+	Track(ctx context.Context, artist, title string) (*models.Track, error)
+	TrackByUUID(ctx context.Context, uuid string) (*models.Track, error)
+	SaveTrack(ctx context.Context, track *models.Track) error
+	FillTrack(ctx context.Context, track *models.Track, generation int64) error
+	DeleteTrack(ctx context.Context, track *models.Track) error
+	DeleteArtistTracks(ctx context.Context, artist string) error
+	AllowRequest(ctx context.Context, key string, rate float64, burst int) (bool, time.Duration, error)
+	ChargeTranslationQuota(ctx context.Context, client string, day time.Time, chars, limit int64) (bool, error)
+	Ping(ctx context.Context) error
+	Close(ctx context.Context) error
+}
+
+type invalidation func(ctx context.Context, backend Backend) error
+
+// Cache stops calling the backend after threshold failed calls in a row
+// and acts as an empty cache until a ping, sent every cooldown, succeeds.
+// Rate limits and quotas are kept per instance meanwhile.
+//
+// Invalidations that didn't reach the backend, including the ones of
+// skipped writes, are replayed before it is used again, so it never serves
+// a track that changed in between.
+type Cache struct {
+	log       *slog.Logger
+	backend   Backend
+	fallback  *memory.Limiter
+	threshold int
+	cooldown  time.Duration
+
+	mu       sync.Mutex
+	failures int
+	degraded bool
+	pending  map[string]invalidation
+	dropped  bool
+}
+
+func New(
+	log *slog.Logger,
+	backend Backend,
+	threshold int,
+	cooldown time.Duration,
+) *Cache {
+	return &Cache{
+		log:       log,
+		backend:   backend,
+		fallback:  memory.NewLimiter(),
+		threshold: threshold,
+		cooldown:  cooldown,
+		pending:   make(map[string]invalidation),
+	}
+}
+
+// Run pings the backend right away and then every cooldown until ctx is
+// cancelled, switching the cache off and on as it goes away and comes back.
+func (c *Cache) Run(ctx context.Context) {
+	const op = "storage.guard.Run"
+
+	log := c.log.With(slog.String("op", op))
+
+	ticker := time.NewTicker(c.cooldown)
+	defer ticker.Stop()
+
+	for {
+		c.probe(ctx, log)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (c *Cache) probe(ctx context.Context, log *slog.Logger) {
+	pingCtx, cancel := context.WithTimeout(ctx, c.cooldown)
+	defer cancel()
+
+	err := c.backend.Ping(pingCtx)
+	if err == nil {
+		err = c.flush(pingCtx)
+	}
+
+	if ctx.Err() != nil {
+		return
+	}
+
+	c.mu.Lock()
+	wasDegraded := c.degraded
+	c.degraded = err != nil
+	c.failures = 0
+	c.mu.Unlock()
+
+	switch {
+	case err != nil && !wasDegraded:
+		log.WarnContext(ctx, "cache is unavailable, serving from the database only", sl.Err(err))
+	case err == nil && wasDegraded:
+		log.InfoContext(ctx, "cache is available again")
+	}
+}
+
+// flush replays the pending invalidations, the ones that fail are kept.
+func (c *Cache) flush(ctx context.Context) error {
+	c.mu.Lock()
+	pending, dropped := c.pending, c.dropped
+	c.pending, c.dropped = make(map[string]invalidation), false
+	c.mu.Unlock()
+
+	if dropped {
+		c.log.WarnContext(ctx, "cache invalidations were dropped, stale entries may be served until they expire")
+	}
+
+	var err error
+
+	for key, invalidate := range pending {
+		if err == nil {
+			err = invalidate(ctx, c.backend)
+		}
+
+		if err != nil {
+			c.queue(key, invalidate)
+		}
+	}
+
+	return err
+}
+
+func (c *Cache) queue(key string, invalidate invalidation) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pending[key]; !ok && len(c.pending) >= maxPending {
+		c.dropped = true
+
+		return
+	}
+
+	c.pending[key] = invalidate
+}
+
+func (c *Cache) available() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return !c.degraded
+}
+
+// record counts the failed calls in a row. Misses and calls cancelled by
+// the caller are not failures.
+func (c *Cache) record(ctx context.Context, err error) {
+	if ctx.Err() != nil {
+		return
+	}
+
+	failed := err != nil &&
+		!errors.Is(err, storage.ErrTrackNotCached) &&
+		!errors.Is(err, storage.ErrArtistTracksNotCached)
+
+	c.mu.Lock()
+
+	if !failed {
+		c.failures = 0
+		c.mu.Unlock()
+
+		return
+	}
+
+	c.failures++
+
+	if c.degraded || c.failures < c.threshold {
+		c.mu.Unlock()
+
+		return
+	}
+
+	c.degraded = true
+	c.mu.Unlock()
+
+	c.log.WarnContext(ctx, "cache keeps failing, serving from the database only",
+		slog.Int("failures", c.threshold),
+		sl.Err(err),
+	)
+}
+
+func (c *Cache) Track(ctx context.Context, artist, title string) (*models.Track, error) {
+	if !c.available() {
+		return nil, storage.ErrTrackNotCached
+	}
+
+	track, err := c.backend.Track(ctx, artist, title)
+	c.record(ctx, err)
+
+	return track, err
+}
+
+func (c *Cache) TrackByUUID(ctx context.Context, uuid string) (*models.Track, error) {
+	if !c.available() {
+		return nil, storage.ErrTrackNotCached
+	}
+
+	track, err := c.backend.TrackByUUID(ctx, uuid)
+	c.record(ctx, err)
+
+	return track, err
+}
+
+func (c *Cache) ArtistTracks(
+	ctx context.Context,
+	artist string,
+	query models.PageQuery,
+) (*models.TrackPage, error) {
+	if !c.available() {
+		return nil, storage.ErrArtistTracksNotCached
+	}
+
+	page, err := c.backend.ArtistTracks(ctx, artist, query)
+	c.record(ctx, err)
+
+	return page, err
+}
+
+// Generation fails while the backend is bypassed, so nothing read from the
+// database meanwhile is cached once it is back.
+func (c *Cache) Generation(ctx context.Context) (int64, error) {
+	if !c.available() {
+		return 0, ErrCacheUnavailable
+	}
+
+	generation, err := c.backend.Generation(ctx)
+	c.record(ctx, err)
+
+	return generation, err
+}
+
+func (c *Cache) SaveArtistTracks(
+	ctx context.Context,
+	artist string,
+	query models.PageQuery,
+	page *models.TrackPage,
+	generation int64,
+) error {
+	if !c.available() {
+		return nil
+	}
+
+	err := c.backend.SaveArtistTracks(ctx, artist, query, page, generation)
+	c.record(ctx, err)
+
+	return err
+}
+
+func (c *Cache) FillTrack(ctx context.Context, track *models.Track, generation int64) error {
+	if !c.available() {
+		return nil
+	}
+
+	err := c.backend.FillTrack(ctx, track, generation)
+	c.record(ctx, err)
+
+	return err
+}
+
+// SaveTrack may update a cached track, so a skipped or failed write
+// invalidates it later instead.
+func (c *Cache) SaveTrack(ctx context.Context, track *models.Track) error {
+	if !c.available() {
+		c.queueTrack(track)
+
+		return nil
+	}
+
+	err := c.backend.SaveTrack(ctx, track)
+	c.record(ctx, err)
+
+	if err != nil {
+		c.queueTrack(track)
+	}
+
+	return err
+}
+
+func (c *Cache) DeleteTrack(ctx context.Context, track *models.Track) error {
+	if !c.available() {
+		c.queueTrack(track)
+
+		return nil
+	}
+
+	err := c.backend.DeleteTrack(ctx, track)
+	c.record(ctx, err)
+
+	if err != nil {
+		c.queueTrack(track)
+	}
+
+	return err
+}
+
+func (c *Cache) DeleteArtistTracks(ctx context.Context, artist string) error {
+	if !c.available() {
+		c.queueArtistTracks(artist)
+
+		return nil
+	}
+
+	err := c.backend.DeleteArtistTracks(ctx, artist)
+	c.record(ctx, err)
+
+	if err != nil {
+		c.queueArtistTracks(artist)
+	}
+
+	return err
+}
+
+// queueTrack queues an invalidation per key of track, so that a later
+// track under the same artist and title, e.g. saved again after a delete,
+// doesn't replace the invalidation of the old UUID.
+func (c *Cache) queueTrack(track *models.Track) {
+	key := &models.Track{UUID: track.UUID, Artist: track.Artist, Title: track.Title}
+
+	for _, cacheKey := range cachekey.TrackKeys(track) {
+		c.queue(cacheKey, func(ctx context.Context, backend Backend) error {
+			return backend.DeleteTrack(ctx, key)
+		})
+	}
+}
+
+func (c *Cache) queueArtistTracks(artist string) {
+	c.queue(cachekey.ArtistTracks(artist), func(ctx context.Context, backend Backend) error {
+		return backend.DeleteArtistTracks(ctx, artist)
+	})
+}
+
+// AllowRequest uses the per-instance limiter while the backend is
+// unavailable or when it fails.
+func (c *Cache) AllowRequest(
+	ctx context.Context,
+	key string,
+	rate float64,
+	burst int,
+) (bool, time.Duration, error) {
+	if c.available() {
+		allowed, retryAfter, err := c.backend.AllowRequest(ctx, key, rate, burst)
+		c.record(ctx, err)
+
+		if err == nil || ctx.Err() != nil {
+			return allowed, retryAfter, err
+		}
+	}
+
+	return c.fallback.AllowRequest(ctx, key, rate, burst)
+}
+
+// ChargeTranslationQuota uses the per-instance quota while the backend is
+// unavailable or when it fails.
+func (c *Cache) ChargeTranslationQuota(
+	ctx context.Context,
+	client string,
+	day time.Time,
+	chars, limit int64,
+) (bool, error) {
+	if c.available() {
+		charged, err := c.backend.ChargeTranslationQuota(ctx, client, day, chars, limit)
+		c.record(ctx, err)
+
+		if err == nil || ctx.Err() != nil {
+			return charged, err
+		}
+	}
+
+	return c.fallback.ChargeTranslationQuota(ctx, client, day, chars, limit)
+}
+
+// Ping fails while the backend is bypassed, even if it already answers
+// again, so health checks show the degraded state.
+func (c *Cache) Ping(ctx context.Context) error {
+	if !c.available() {
+		return ErrCacheUnavailable
+	}
+
+	return c.backend.Ping(ctx)
+}
+
+func (c *Cache) Close(ctx context.Context) error {
+	return c.backend.Close(ctx)
+}
@@ -0,0 +1,398 @@
+package guard
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/logger/slogdiscard"
+	"lyrics-library/internal/storage"
+)
+
+var errDown = errors.New("dial tcp 10.0.0.6:6379: connect: connection refused")
+
+// fakeBackend fails every call with err and records the invalidations it
+// receives.
+type fakeBackend struct {
+	mu      sync.Mutex
+	err     error
+	calls   int
+	deleted []string
+	artists []string
+}
+
+func (b *fakeBackend) setErr(err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.err = err
+}
+
+func (b *fakeBackend) call() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.calls++
+
+	return b.err
+}
+
+func (b *fakeBackend) callCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.calls
+}
+
+func (b *fakeBackend) Generation(context.Context) (int64, error) {
+	return 1, b.call()
+}
+
+func (b *fakeBackend) SaveArtistTracks(context.Context, string, models.PageQuery, *models.TrackPage, int64) error {
+	return b.call()
+}
+
+func (b *fakeBackend) ArtistTracks(context.Context, string, models.PageQuery) (*models.TrackPage, error) {
+	if err := b.call(); err != nil {
+		return nil, err
+	}
+
+	return nil, storage.ErrArtistTracksNotCached
+}
+
+func (b *fakeBackend) Track(context.Context, string, string) (*models.Track, error) {
+	if err := b.call(); err != nil {
+		return nil, err
+	}
+
+	return nil, storage.ErrTrackNotCached
+}
+
+func (b *fakeBackend) TrackByUUID(context.Context, string) (*models.Track, error) {
+	if err := b.call(); err != nil {
+		return nil, err
+	}
+
+	return nil, storage.ErrTrackNotCached
+}
+
+func (b *fakeBackend) SaveTrack(context.Context, *models.Track) error {
+	return b.call()
+}
+
+func (b *fakeBackend) FillTrack(context.Context, *models.Track, int64) error {
+	return b.call()
+}
+
+func (b *fakeBackend) DeleteTrack(_ context.Context, track *models.Track) error {
+	if err := b.call(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.deleted = append(b.deleted, track.UUID)
+
+	return nil
+}
+
+func (b *fakeBackend) DeleteArtistTracks(_ context.Context, artist string) error {
+	if err := b.call(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.artists = append(b.artists, artist)
+
+	return nil
+}
+
+func (b *fakeBackend) AllowRequest(context.Context, string, float64, int) (bool, time.Duration, error) {
+	return true, 0, b.call()
+}
+
+func (b *fakeBackend) ChargeTranslationQuota(context.Context, string, time.Time, int64, int64) (bool, error) {
+	return true, b.call()
+}
+
+func (b *fakeBackend) Ping(context.Context) error {
+	return b.call()
+}
+
+func (b *fakeBackend) Close(context.Context) error {
+	return nil
+}
+
+func newCache(backend Backend) *Cache {
+	return New(slogdiscard.NewDiscardLogger(), backend, 3, time.Second)
+}
+
+func TestCacheDegradesAfterThreshold(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{}
+	c := newCache(backend)
+
+	// Misses are not failures and a success resets the count.
+	for range 5 {
+		if _, err := c.Track(ctx, "artist", "title"); !errors.Is(err, storage.ErrTrackNotCached) {
+			t.Fatalf("Track() error = %v, want %v", err, storage.ErrTrackNotCached)
+		}
+	}
+
+	backend.setErr(errDown)
+
+	for i := range 2 {
+		if _, err := c.TrackByUUID(ctx, "u1"); !errors.Is(err, errDown) {
+			t.Fatalf("TrackByUUID() #%d error = %v, want %v", i, err, errDown)
+		}
+	}
+
+	if !c.available() {
+		t.Fatal("available() = false before the threshold is reached")
+	}
+
+	if _, err := c.TrackByUUID(ctx, "u1"); !errors.Is(err, errDown) {
+		t.Fatalf("TrackByUUID() error = %v, want %v", err, errDown)
+	}
+
+	if c.available() {
+		t.Fatal("available() = true after the threshold is reached")
+	}
+
+	calls := backend.callCount()
+
+	if _, err := c.Track(ctx, "artist", "title"); !errors.Is(err, storage.ErrTrackNotCached) {
+		t.Errorf("Track() error = %v, want %v", err, storage.ErrTrackNotCached)
+	}
+
+	if _, err := c.ArtistTracks(ctx, "artist", models.PageQuery{}); !errors.Is(err, storage.ErrArtistTracksNotCached) {
+		t.Errorf("ArtistTracks() error = %v, want %v", err, storage.ErrArtistTracksNotCached)
+	}
+
+	if _, err := c.Generation(ctx); !errors.Is(err, ErrCacheUnavailable) {
+		t.Errorf("Generation() error = %v, want %v", err, ErrCacheUnavailable)
+	}
+
+	if err := c.FillTrack(ctx, &models.Track{UUID: "u1"}, 1); err != nil {
+		t.Errorf("FillTrack() error = %v", err)
+	}
+
+	if err := c.Ping(ctx); !errors.Is(err, ErrCacheUnavailable) {
+		t.Errorf("Ping() error = %v, want %v", err, ErrCacheUnavailable)
+	}
+
+	if got := backend.callCount(); got != calls {
+		t.Errorf("backend calls while degraded = %d, want 0", got-calls)
+	}
+}
+
+func TestCacheCancelledCallsAreNotFailures(t *testing.T) {
+	backend := &fakeBackend{err: context.Canceled}
+	c := newCache(backend)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	for range 5 {
+		_, _ = c.Track(ctx, "artist", "title")
+	}
+
+	if !c.available() {
+		t.Error("available() = false after cancelled calls")
+	}
+}
+
+func TestCacheRecovers(t *testing.T) {
+	ctx := context.Background()
+	log := slogdiscard.NewDiscardLogger()
+	backend := &fakeBackend{err: errDown}
+	c := newCache(backend)
+
+	c.probe(ctx, log)
+
+	if c.available() {
+		t.Fatal("available() = true after a failed ping")
+	}
+
+	if err := c.DeleteTrack(ctx, &models.Track{UUID: "u1", Artist: "artist", Title: "title"}); err != nil {
+		t.Fatalf("DeleteTrack() error = %v", err)
+	}
+
+	if err := c.DeleteArtistTracks(ctx, "artist"); err != nil {
+		t.Fatalf("DeleteArtistTracks() error = %v", err)
+	}
+
+	backend.setErr(nil)
+
+	c.probe(ctx, log)
+
+	if !c.available() {
+		t.Fatal("available() = false after a successful ping")
+	}
+
+	if !slices.Contains(backend.deleted, "u1") {
+		t.Errorf("deleted tracks = %q, want u1 invalidated on recovery", backend.deleted)
+	}
+
+	if !slices.Equal(backend.artists, []string{"artist"}) {
+		t.Errorf("deleted artists = %q, want [artist]", backend.artists)
+	}
+
+	if len(c.pending) != 0 {
+		t.Errorf("pending invalidations = %d, want 0", len(c.pending))
+	}
+}
+
+func TestCacheInvalidatesEveryUUIDOfTrack(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{err: errDown}
+	c := newCache(backend)
+
+	c.probe(ctx, slogdiscard.NewDiscardLogger())
+
+	// The track is deleted and saved again under a new UUID.
+	_ = c.DeleteTrack(ctx, &models.Track{UUID: "old", Artist: "artist", Title: "title"})
+	_ = c.SaveTrack(ctx, &models.Track{UUID: "new", Artist: "Artist", Title: "Title"})
+
+	backend.setErr(nil)
+
+	if err := c.flush(ctx); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	for _, uuid := range []string{"old", "new"} {
+		if !slices.Contains(backend.deleted, uuid) {
+			t.Errorf("deleted tracks = %q, want %s invalidated", backend.deleted, uuid)
+		}
+	}
+}
+
+func TestCacheFlushKeepsFailedInvalidations(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{err: errDown}
+	c := newCache(backend)
+
+	if err := c.SaveTrack(ctx, &models.Track{UUID: "u1", Artist: "artist", Title: "title"}); !errors.Is(err, errDown) {
+		t.Fatalf("SaveTrack() error = %v, want %v", err, errDown)
+	}
+
+	if err := c.DeleteArtistTracks(ctx, "artist"); !errors.Is(err, errDown) {
+		t.Fatalf("DeleteArtistTracks() error = %v, want %v", err, errDown)
+	}
+
+	want := len(c.pending)
+	if want == 0 {
+		t.Fatal("pending invalidations = 0 after failed writes")
+	}
+
+	if err := c.flush(ctx); !errors.Is(err, errDown) {
+		t.Fatalf("flush() error = %v, want %v", err, errDown)
+	}
+
+	if got := len(c.pending); got != want {
+		t.Errorf("pending invalidations after a failed flush = %d, want %d", got, want)
+	}
+
+	backend.setErr(nil)
+
+	if err := c.flush(ctx); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if got := len(c.pending); got != 0 {
+		t.Errorf("pending invalidations after a flush = %d, want 0", got)
+	}
+}
+
+func TestCacheDropsInvalidationsAboveMaxPending(t *testing.T) {
+	ctx := context.Background()
+	backend := &fakeBackend{err: errDown}
+	c := newCache(backend)
+
+	c.probe(ctx, slogdiscard.NewDiscardLogger())
+
+	for i := range maxPending + 10 {
+		_ = c.DeleteArtistTracks(ctx, fmt.Sprintf("artist %d", i))
+	}
+
+	if got := len(c.pending); got != maxPending {
+		t.Errorf("pending invalidations = %d, want %d", got, maxPending)
+	}
+
+	if !c.dropped {
+		t.Error("dropped = false after exceeding maxPending")
+	}
+
+	// A key already pending is still updated.
+	_ = c.DeleteArtistTracks(ctx, "artist 0")
+
+	if got := len(c.pending); got != maxPending {
+		t.Errorf("pending invalidations = %d, want %d", got, maxPending)
+	}
+
+	backend.setErr(nil)
+
+	if err := c.flush(ctx); err != nil {
+		t.Fatalf("flush() error = %v", err)
+	}
+
+	if c.dropped {
+		t.Error("dropped = true after a flush")
+	}
+
+	if got := len(backend.artists); got != maxPending {
+		t.Errorf("replayed invalidations = %d, want %d", got, maxPending)
+	}
+}
+
+func TestCacheFallsBackToLocalLimits(t *testing.T) {
+	ctx := context.Background()
+	day := time.Date(2026, 10, 16, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		degrade bool
+	}{
+		{name: "backend fails"},
+		{name: "degraded", degrade: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			backend := &fakeBackend{err: errDown}
+			c := newCache(backend)
+
+			if tt.degrade {
+				c.probe(ctx, slogdiscard.NewDiscardLogger())
+			}
+
+			allowed, _, err := c.AllowRequest(ctx, "client", 0.001, 1)
+			if err != nil || !allowed {
+				t.Fatalf("AllowRequest() = %v, %v, want allowed", allowed, err)
+			}
+
+			allowed, retryAfter, err := c.AllowRequest(ctx, "client", 0.001, 1)
+			if err != nil || allowed || retryAfter <= 0 {
+				t.Errorf("AllowRequest() = %v, %v, %v, want denied by the local limiter", allowed, retryAfter, err)
+			}
+
+			charged, err := c.ChargeTranslationQuota(ctx, "client", day, 80, 100)
+			if err != nil || !charged {
+				t.Fatalf("ChargeTranslationQuota() = %v, %v, want charged", charged, err)
+			}
+
+			charged, err = c.ChargeTranslationQuota(ctx, "client", day, 80, 100)
+			if err != nil || charged {
+				t.Errorf("ChargeTranslationQuota() = %v, %v, want refused by the local quota", charged, err)
+			}
+		})
+	}
+}
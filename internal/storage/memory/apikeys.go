@@ -0,0 +1,88 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/storage"
+)
+
+type apiKeyRecord struct {
+	key  *models.APIKey
+	hash string
+}
+
+func (s *Storage) CreateAPIKey(_ context.Context, key *models.APIKey, hash string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key.ID = uuid.NewString()
+	key.CreatedAt = time.Now().UTC()
+
+	s.apiKeys = append(s.apiKeys, &apiKeyRecord{key: copyAPIKey(key), hash: hash})
+
+	return nil
+}
+
+func (s *Storage) APIKeyByHash(_ context.Context, hash string) (*models.APIKey, error) {
+	const op = "storage.memory.APIKeyByHash"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, rec := range s.apiKeys {
+		if rec.hash == hash {
+			return copyAPIKey(rec.key), nil
+		}
+	}
+
+	return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+}
+
+func (s *Storage) APIKeys(_ context.Context) ([]*models.APIKey, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	keys := make([]*models.APIKey, 0, len(s.apiKeys))
+	for _, rec := range s.apiKeys {
+		keys = append(keys, copyAPIKey(rec.key))
+	}
+
+	return keys, nil
+}
+
+func (s *Storage) RevokeAPIKey(_ context.Context, id string) error {
+	const op = "storage.memory.RevokeAPIKey"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, rec := range s.apiKeys {
+		if strings.EqualFold(rec.key.ID, id) && rec.key.RevokedAt == nil {
+			now := time.Now().UTC()
+			rec.key.RevokedAt = &now
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+}
+
+func copyAPIKey(key *models.APIKey) *models.APIKey {
+	copied := *key
+	copied.Scopes = slices.Clone(key.Scopes)
+
+	if key.RevokedAt != nil {
+		revokedAt := *key.RevokedAt
+		copied.RevokedAt = &revokedAt
+	}
+
+	return &copied
+}
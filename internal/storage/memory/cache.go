@@ -0,0 +1,321 @@
+package memory
+
+import (
+	"container/list"
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/cachekey"
+)
+
+type entry struct {
+	key       string
+	expiresAt time.Time
+	// value holds a JSON document for tracks and a page field to JSON
+	// document map for artist's pages, so callers never share memory with
+	// the cache.
+	value any
+}
+
+// Cache is a bounded LRU cache with per-entry TTLs. It uses the cachekey
+// layout and the same fill rules as the Redis cache.
+type Cache struct {
+	mu              sync.Mutex
+	generation      int64
+	capacity        int
+	items           map[string]*list.Element
+	order           *list.List
+	trackTTL        time.Duration
+	artistTracksTTL time.Duration
+	metrics         *metrics.Metrics
+}
+
+func NewCache(
+	capacity int,
+	trackTTL, artistTracksTTL time.Duration,
+	m *metrics.Metrics,
+) *Cache {
+	return &Cache{
+		capacity:        capacity,
+		items:           make(map[string]*list.Element),
+		order:           list.New(),
+		trackTTL:        trackTTL,
+		artistTracksTTL: artistTracksTTL,
+		metrics:         m,
+	}
+}
+
+func (c *Cache) Generation(_ context.Context) (int64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation, nil
+}
+
+// SaveTrack writes a track that has just been changed in the storage. A
+// write that lost the race against a newer version of the track is
+// skipped.
+func (c *Cache) SaveTrack(_ context.Context, track *models.Track) error {
+	const op = "storage.memory.cache.SaveTrack"
+
+	data, err := json.Marshal(track)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	keys := cachekey.TrackKeys(track)
+
+	for _, key := range keys {
+		if c.newerCached(key, track) {
+			return nil
+		}
+	}
+
+	c.generation++
+
+	for _, key := range keys {
+		c.set(key, data, c.trackTTL)
+	}
+
+	return nil
+}
+
+// FillTrack caches a track read from the storage, unless the cache has
+// been written or invalidated since generation was taken.
+func (c *Cache) FillTrack(_ context.Context, track *models.Track, generation int64) error {
+	const op = "storage.memory.cache.FillTrack"
+
+	data, err := json.Marshal(track)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return nil
+	}
+
+	for _, key := range cachekey.TrackKeys(track) {
+		c.set(key, data, c.trackTTL)
+	}
+
+	return nil
+}
+
+func (c *Cache) Track(_ context.Context, artist, title string) (*models.Track, error) {
+	const op = "storage.memory.cache.Track"
+
+	return c.track(op, cachekey.Track(artist, title))
+}
+
+func (c *Cache) TrackByUUID(_ context.Context, uuid string) (*models.Track, error) {
+	const op = "storage.memory.cache.TrackByUUID"
+
+	return c.track(op, cachekey.TrackUUID(uuid))
+}
+
+func (c *Cache) DeleteTrack(_ context.Context, track *models.Track) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	for _, key := range cachekey.TrackKeys(track) {
+		c.delete(key)
+	}
+
+	return nil
+}
+
+// SaveArtistTracks caches a page read from the storage, unless the cache
+// has been written or invalidated since generation was taken.
+func (c *Cache) SaveArtistTracks(
+	_ context.Context,
+	artist string,
+	query models.PageQuery,
+	page *models.TrackPage,
+	generation int64,
+) error {
+	const op = "storage.memory.cache.SaveArtistTracks"
+
+	data, err := json.Marshal(page)
+	if err != nil {
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return nil
+	}
+
+	key := cachekey.ArtistTracks(artist)
+
+	if pages, ok := c.get(key).(map[string][]byte); ok {
+		pages[cachekey.PageField(query)] = data
+
+		return nil
+	}
+
+	c.set(key, map[string][]byte{cachekey.PageField(query): data}, c.artistTracksTTL)
+
+	return nil
+}
+
+func (c *Cache) ArtistTracks(
+	_ context.Context,
+	artist string,
+	query models.PageQuery,
+) (*models.TrackPage, error) {
+	const op = "storage.memory.cache.ArtistTracks"
+
+	c.mu.Lock()
+	pages, _ := c.get(cachekey.ArtistTracks(artist)).(map[string][]byte)
+	data, ok := pages[cachekey.PageField(query)]
+	c.mu.Unlock()
+
+	c.metrics.ObserveCacheLookup(metrics.CacheArtistTracks, ok)
+
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrArtistTracksNotCached)
+	}
+
+	var page models.TrackPage
+	if err := json.Unmarshal(data, &page); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &page, nil
+}
+
+func (c *Cache) DeleteArtistTracks(_ context.Context, artist string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+
+	c.delete(cachekey.ArtistTracks(artist))
+
+	return nil
+}
+
+func (c *Cache) Ping(_ context.Context) error {
+	return nil
+}
+
+func (c *Cache) Close(_ context.Context) error {
+	return nil
+}
+
+func (c *Cache) track(op, key string) (*models.Track, error) {
+	c.mu.Lock()
+	data, ok := c.get(key).([]byte)
+	c.mu.Unlock()
+
+	c.metrics.ObserveCacheLookup(metrics.CacheTrack, ok)
+
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotCached)
+	}
+
+	var track models.Track
+	if err := json.Unmarshal(data, &track); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &track, nil
+}
+
+// newerCached reports whether key holds a newer version of track. It must
+// be called with c.mu held.
+func (c *Cache) newerCached(key string, track *models.Track) bool {
+	data, ok := c.get(key).([]byte)
+	if !ok {
+		return false
+	}
+
+	var cached struct {
+		UUID    string `json:"uuid"`
+		Version int64  `json:"version"`
+	}
+
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return false
+	}
+
+	return cached.UUID == track.UUID && cached.Version > track.Version
+}
+
+// get returns the live value under key and marks it as recently used.
+// It must be called with c.mu held.
+func (c *Cache) get(key string) any {
+	elem, ok := c.items[key]
+	if !ok {
+		return nil
+	}
+
+	e := elem.Value.(*entry)
+	if !e.expiresAt.IsZero() && time.Now().After(e.expiresAt) {
+		c.order.Remove(elem)
+		delete(c.items, key)
+
+		return nil
+	}
+
+	c.order.MoveToFront(elem)
+
+	return e.value
+}
+
+// set stores value under key, evicting the least recently used entries
+// above capacity. A zero ttl means no expiration. It must be called with
+// c.mu held.
+func (c *Cache) set(key string, value any, ttl time.Duration) {
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = time.Now().Add(ttl)
+	}
+
+	if elem, ok := c.items[key]; ok {
+		e := elem.Value.(*entry)
+		e.value = value
+		e.expiresAt = expiresAt
+
+		c.order.MoveToFront(elem)
+
+		return
+	}
+
+	c.items[key] = c.order.PushFront(&entry{
+		key:       key,
+		expiresAt: expiresAt,
+		value:     value,
+	})
+
+	for c.capacity > 0 && c.order.Len() > c.capacity {
+		oldest := c.order.Back()
+
+		c.order.Remove(oldest)
+		delete(c.items, oldest.Value.(*entry).key)
+	}
+}
+
+// delete must be called with c.mu held.
+func (c *Cache) delete(key string) {
+	if elem, ok := c.items[key]; ok {
+		c.order.Remove(elem)
+		delete(c.items, key)
+	}
+}
@@ -0,0 +1,211 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/memory"
+)
+
+func newCache() *memory.Cache {
+	return memory.NewCache(100, time.Minute, time.Minute, nil)
+}
+
+func cachedVersion(t *testing.T, c *memory.Cache, uuid string) int64 {
+	t.Helper()
+
+	track, err := c.TrackByUUID(context.Background(), uuid)
+	if errors.Is(err, storage.ErrTrackNotCached) {
+		return 0
+	}
+
+	if err != nil {
+		t.Fatalf("TrackByUUID() error = %v", err)
+	}
+
+	return track.Version
+}
+
+func TestCacheFillTrack(t *testing.T) {
+	ctx := context.Background()
+	stale := &models.Track{UUID: "u1", Artist: "artist", Title: "title", Version: 1}
+
+	tests := []struct {
+		name  string
+		after func(c *memory.Cache)
+		want  int64
+	}{
+		{
+			name:  "nothing happened since the read",
+			after: func(c *memory.Cache) {},
+			want:  1,
+		},
+		{
+			name: "track updated since the read",
+			after: func(c *memory.Cache) {
+				_ = c.SaveTrack(ctx, &models.Track{UUID: "u1", Artist: "artist", Title: "title", Version: 2})
+			},
+			want: 2,
+		},
+		{
+			name: "track deleted since the read",
+			after: func(c *memory.Cache) {
+				_ = c.DeleteTrack(ctx, stale)
+			},
+			want: 0,
+		},
+		{
+			name: "artist pages invalidated since the read",
+			after: func(c *memory.Cache) {
+				_ = c.DeleteArtistTracks(ctx, "artist")
+			},
+			want: 0,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newCache()
+
+			generation, err := c.Generation(ctx)
+			if err != nil {
+				t.Fatalf("Generation() error = %v", err)
+			}
+
+			tt.after(c)
+
+			if err := c.FillTrack(ctx, stale, generation); err != nil {
+				t.Fatalf("FillTrack() error = %v", err)
+			}
+
+			if got := cachedVersion(t, c, "u1"); got != tt.want {
+				t.Errorf("cached version = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheSaveTrackKeepsNewerVersion(t *testing.T) {
+	ctx := context.Background()
+	c := newCache()
+
+	newer := &models.Track{UUID: "u1", Artist: "artist", Title: "title", Version: 3}
+	older := &models.Track{UUID: "u1", Artist: "artist", Title: "title", Version: 2}
+
+	if err := c.SaveTrack(ctx, newer); err != nil {
+		t.Fatalf("SaveTrack() error = %v", err)
+	}
+
+	if err := c.SaveTrack(ctx, older); err != nil {
+		t.Fatalf("SaveTrack() error = %v", err)
+	}
+
+	if got := cachedVersion(t, c, "u1"); got != 3 {
+		t.Errorf("cached version = %d, want 3", got)
+	}
+
+	// Another track under the same name replaces the cached one.
+	other := &models.Track{UUID: "u2", Artist: "artist", Title: "title", Version: 1}
+	if err := c.SaveTrack(ctx, other); err != nil {
+		t.Fatalf("SaveTrack() error = %v", err)
+	}
+
+	track, err := c.Track(ctx, "artist", "title")
+	if err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	if track.UUID != "u2" {
+		t.Errorf("cached uuid = %q, want %q", track.UUID, "u2")
+	}
+}
+
+func TestCacheSaveArtistTracksAfterInvalidation(t *testing.T) {
+	ctx := context.Background()
+	c := newCache()
+
+	query := models.PageQuery{Limit: 10, Sort: models.SortByTitle}
+	page := &models.TrackPage{Tracks: []*models.Track{{UUID: "u1", Artist: "artist", Title: "title"}}}
+
+	generation, err := c.Generation(ctx)
+	if err != nil {
+		t.Fatalf("Generation() error = %v", err)
+	}
+
+	// A track of the artist is saved while the page is being read.
+	if err := c.DeleteArtistTracks(ctx, "artist"); err != nil {
+		t.Fatalf("DeleteArtistTracks() error = %v", err)
+	}
+
+	if err := c.SaveArtistTracks(ctx, "artist", query, page, generation); err != nil {
+		t.Fatalf("SaveArtistTracks() error = %v", err)
+	}
+
+	if _, err := c.ArtistTracks(ctx, "artist", query); !errors.Is(err, storage.ErrArtistTracksNotCached) {
+		t.Fatalf("ArtistTracks() error = %v, want %v", err, storage.ErrArtistTracksNotCached)
+	}
+
+	generation, _ = c.Generation(ctx)
+
+	if err := c.SaveArtistTracks(ctx, "artist", query, page, generation); err != nil {
+		t.Fatalf("SaveArtistTracks() error = %v", err)
+	}
+
+	if _, err := c.ArtistTracks(ctx, "artist", query); err != nil {
+		t.Errorf("ArtistTracks() error = %v", err)
+	}
+}
+
+func TestCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	c := memory.NewCache(4, time.Minute, time.Minute, nil)
+
+	// Every track takes two entries, by identity and by UUID.
+	for _, uuid := range []string{"u1", "u2"} {
+		if err := c.SaveTrack(ctx, &models.Track{UUID: uuid, Artist: "artist", Title: uuid, Version: 1}); err != nil {
+			t.Fatalf("SaveTrack() error = %v", err)
+		}
+	}
+
+	// Reading u1 makes u2 the least recently used.
+	if got := cachedVersion(t, c, "u1"); got != 1 {
+		t.Fatalf("cached u1 version = %d, want 1", got)
+	}
+
+	if _, err := c.Track(ctx, "artist", "u1"); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	if err := c.SaveTrack(ctx, &models.Track{UUID: "u3", Artist: "artist", Title: "u3", Version: 1}); err != nil {
+		t.Fatalf("SaveTrack() error = %v", err)
+	}
+
+	for uuid, want := range map[string]int64{"u1": 1, "u2": 0, "u3": 1} {
+		if got := cachedVersion(t, c, uuid); got != want {
+			t.Errorf("cached %s version = %d, want %d", uuid, got, want)
+		}
+	}
+}
+
+func TestCacheExpiresEntries(t *testing.T) {
+	ctx := context.Background()
+	c := memory.NewCache(100, 20*time.Millisecond, time.Minute, nil)
+
+	if err := c.SaveTrack(ctx, &models.Track{UUID: "u1", Artist: "artist", Title: "title", Version: 1}); err != nil {
+		t.Fatalf("SaveTrack() error = %v", err)
+	}
+
+	if got := cachedVersion(t, c, "u1"); got != 1 {
+		t.Fatalf("cached version = %d, want 1", got)
+	}
+
+	time.Sleep(40 * time.Millisecond)
+
+	if _, err := c.Track(ctx, "artist", "title"); !errors.Is(err, storage.ErrTrackNotCached) {
+		t.Errorf("Track() error = %v, want %v", err, storage.ErrTrackNotCached)
+	}
+}
@@ -0,0 +1,139 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/storage"
+)
+
+func (s *Storage) CreateJob(_ context.Context, job *models.Job) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now().UTC()
+
+	job.ID = uuid.NewString()
+	job.Status = models.JobPending
+	job.CreatedAt = now
+	job.UpdatedAt = now
+
+	s.jobs[job.ID] = copyJob(job)
+
+	return nil
+}
+
+func (s *Storage) Job(_ context.Context, id string) (*models.Job, error) {
+	const op = "storage.memory.Job"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	job, ok := s.jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrJobNotFound)
+	}
+
+	return copyJob(job), nil
+}
+
+func (s *Storage) ClaimJob(_ context.Context, retryDelay time.Duration) (*models.Job, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	retryAfter := time.Now().Add(-retryDelay)
+
+	var oldest *models.Job
+	for _, job := range s.jobs {
+		if job.Status != models.JobPending {
+			continue
+		}
+
+		if job.Attempts > 0 && !job.UpdatedAt.Before(retryAfter) {
+			continue
+		}
+
+		if oldest == nil || job.CreatedAt.Before(oldest.CreatedAt) {
+			oldest = job
+		}
+	}
+
+	if oldest == nil {
+		return nil, storage.ErrNoPendingJobs
+	}
+
+	oldest.Status = models.JobRunning
+	oldest.Attempts++
+	oldest.UpdatedAt = time.Now().UTC()
+
+	return copyJob(oldest), nil
+}
+
+func (s *Storage) UpdateJob(_ context.Context, job *models.Job) error {
+	const op = "storage.memory.UpdateJob"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	stored, ok := s.jobs[job.ID]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrJobNotFound)
+	}
+
+	stored.Status = job.Status
+	stored.TrackUUID = job.TrackUUID
+	stored.Error = job.Error
+	stored.UpdatedAt = time.Now().UTC()
+
+	job.UpdatedAt = stored.UpdatedAt
+
+	return nil
+}
+
+func (s *Storage) RequeueStaleJobs(_ context.Context, olderThan time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline := time.Now().Add(-olderThan)
+
+	var requeued int64
+	for _, job := range s.jobs {
+		if job.Status == models.JobRunning && job.UpdatedAt.Before(deadline) {
+			job.Status = models.JobPending
+			job.UpdatedAt = time.Now().UTC()
+			requeued++
+		}
+	}
+
+	return requeued, nil
+}
+
+func (s *Storage) DeleteFinishedJobs(_ context.Context, olderThan time.Duration) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	deadline := time.Now().Add(-olderThan)
+
+	var deleted int64
+	for id, job := range s.jobs {
+		finished := job.Status == models.JobSucceeded || job.Status == models.JobFailed
+		if finished && job.UpdatedAt.Before(deadline) {
+			delete(s.jobs, id)
+			deleted++
+		}
+	}
+
+	return deleted, nil
+}
+
+func copyJob(job *models.Job) *models.Job {
+	copied := *job
+	copied.TargetLangs = slices.Clone(job.TargetLangs)
+
+	return &copied
+}
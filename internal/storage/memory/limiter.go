@@ -0,0 +1,110 @@
+package memory
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepEvery is the number of AllowRequest calls between removals of idle
+// buckets.
+const sweepEvery = 1024
+
+type bucket struct {
+	tokens    float64
+	updatedAt time.Time
+	// fullAt is when the bucket refills completely at its own rate.
+	fullAt time.Time
+}
+
+// Limiter keeps rate limit buckets and translation quotas in memory. Limits
+// are per instance, the Redis storage shares them between instances.
+type Limiter struct {
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	calls   int
+
+	quotaDay string
+	quotas   map[string]int64
+}
+
+func NewLimiter() *Limiter {
+	return &Limiter{
+		buckets: make(map[string]*bucket),
+		quotas:  make(map[string]int64),
+	}
+}
+
+func (l *Limiter) AllowRequest(
+	_ context.Context,
+	key string,
+	rate float64,
+	burst int,
+) (bool, time.Duration, error) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.calls++
+	if l.calls%sweepEvery == 0 {
+		l.sweep(now)
+	}
+
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(burst), updatedAt: now}
+		l.buckets[key] = b
+	}
+
+	b.tokens = min(float64(burst), b.tokens+now.Sub(b.updatedAt).Seconds()*rate)
+	b.updatedAt = now
+
+	allowed := b.tokens >= 1
+	if allowed {
+		b.tokens--
+	}
+
+	b.fullAt = now.Add(time.Duration((float64(burst) - b.tokens) / rate * float64(time.Second)))
+
+	if allowed {
+		return true, 0, nil
+	}
+
+	return false, time.Duration((1 - b.tokens) / rate * float64(time.Second)), nil
+}
+
+// sweep drops the buckets that have refilled completely, they are the same
+// as missing ones. Buckets of different limits share the map, so each one
+// is checked against its own refill time.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if !now.Before(b.fullAt) {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) ChargeTranslationQuota(
+	_ context.Context,
+	client string,
+	day time.Time,
+	chars, limit int64,
+) (bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if d := day.Format(time.DateOnly); d != l.quotaDay {
+		l.quotaDay = d
+		clear(l.quotas)
+	}
+
+	used := l.quotas[client]
+	if used+chars > limit {
+		return false, nil
+	}
+
+	l.quotas[client] = used + chars
+
+	return true, nil
+}
@@ -0,0 +1,42 @@
+package memory_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"lyrics-library/internal/storage/memory"
+)
+
+func TestLimiterSweepKeepsBucketsOfSlowerLimits(t *testing.T) {
+	ctx := context.Background()
+	l := memory.NewLimiter()
+
+	// Drain a slow bucket that needs two seconds to refill.
+	for range 2 {
+		if allowed, _, _ := l.AllowRequest(ctx, "key:slow", 1, 2); !allowed {
+			t.Fatal("AllowRequest() denied a request within the burst")
+		}
+	}
+
+	time.Sleep(time.Millisecond)
+
+	// A fast limit refills within a nanosecond. Its calls trigger the
+	// sweep, which must not take the slow bucket for a refilled one.
+	for range 1022 {
+		_, _, _ = l.AllowRequest(ctx, "ip:fast", 1e9, 1)
+	}
+
+	allowed, retryAfter, err := l.AllowRequest(ctx, "key:slow", 1, 2)
+	if err != nil {
+		t.Fatalf("AllowRequest() error = %v", err)
+	}
+
+	if allowed {
+		t.Fatal("AllowRequest() allowed a request of a drained bucket after a sweep")
+	}
+
+	if retryAfter <= 0 || retryAfter > time.Second {
+		t.Errorf("AllowRequest() retry after = %s, want up to a second", retryAfter)
+	}
+}
@@ -0,0 +1,553 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"github.com/google/uuid"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/identity"
+	"lyrics-library/internal/lib/pagination"
+	"lyrics-library/internal/storage"
+)
+
+const (
+	translationWeight = 0.4
+
+	markStart = "<mark>"
+	markEnd   = "</mark>"
+)
+
+type record struct {
+	id    int64
+	track *models.Track
+}
+
+// Storage keeps tracks, jobs and API keys in memory. It follows the semantics of the
+// Postgres storage and is meant for local runs and tests.
+type Storage struct {
+	mu     sync.RWMutex
+	nextID int64
+	byKey  map[string]*record
+	byUUID map[string]*record
+	jobs   map[string]*models.Job
+
+	apiKeys []*apiKeyRecord
+}
+
+func New() *Storage {
+	return &Storage{
+		byKey:  make(map[string]*record),
+		byUUID: make(map[string]*record),
+		jobs:   make(map[string]*models.Job),
+	}
+}
+
+func (s *Storage) SaveTrack(_ context.Context, track *models.Track) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := identity.TrackKey(track.Artist, track.Title)
+
+	if existing, ok := s.byKey[key]; ok {
+		*track = *copyTrack(existing.track)
+
+		return false, nil
+	}
+
+	now := time.Now().UTC()
+
+	s.nextID++
+
+	track.UUID = uuid.NewString()
+	track.Version = 1
+	track.CreatedAt = now
+	track.UpdatedAt = now
+
+	rec := &record{id: s.nextID, track: copyTrack(track)}
+	if rec.track.Translations == nil {
+		rec.track.Translations = map[string][]string{}
+	}
+
+	s.byKey[key] = rec
+	s.byUUID[track.UUID] = rec
+
+	return true, nil
+}
+
+func (s *Storage) Track(_ context.Context, artist, title string) (*models.Track, error) {
+	const op = "storage.memory.Track"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.byKey[identity.TrackKey(artist, title)]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	return copyTrack(rec.track), nil
+}
+
+func (s *Storage) TrackByUUID(_ context.Context, uuid string) (*models.Track, error) {
+	const op = "storage.memory.TrackByUUID"
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	rec, ok := s.byUUID[uuid]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	return copyTrack(rec.track), nil
+}
+
+func (s *Storage) SaveTranslation(_ context.Context, uuid, lang string, lines []string) error {
+	const op = "storage.memory.SaveTranslation"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.byUUID[uuid]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	if _, ok := rec.track.Translations[lang]; !ok {
+		rec.track.Translations[lang] = slices.Clone(lines)
+		rec.track.Version++
+		rec.track.UpdatedAt = time.Now().UTC()
+	}
+
+	return nil
+}
+
+func (s *Storage) SaveSyncedLyrics(_ context.Context, uuid string, lines []models.SyncedLine) error {
+	const op = "storage.memory.SaveSyncedLyrics"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.byUUID[uuid]
+	if !ok {
+		return fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	rec.track.Synced = slices.Clone(lines)
+	rec.track.Version++
+	rec.track.UpdatedAt = time.Now().UTC()
+
+	return nil
+}
+
+func (s *Storage) UpdateTrack(
+	_ context.Context,
+	track *models.Track,
+	version int64,
+	langs []string,
+) (*models.Track, error) {
+	const op = "storage.memory.UpdateTrack"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.byUUID[track.UUID]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	if rec.track.Version != version {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrVersionConflict)
+	}
+
+	oldKey := identity.TrackKey(rec.track.Artist, rec.track.Title)
+	newKey := identity.TrackKey(track.Artist, track.Title)
+
+	if existing, ok := s.byKey[newKey]; ok && existing != rec {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackExists)
+	}
+
+	delete(s.byKey, oldKey)
+	s.byKey[newKey] = rec
+
+	rec.track.Artist = track.Artist
+	rec.track.Title = track.Title
+	rec.track.Lyrics = slices.Clone(track.Lyrics)
+
+	for _, lang := range langs {
+		if _, ok := rec.track.Translations[lang]; !ok {
+			continue
+		}
+
+		rec.track.Translations[lang] = slices.Clone(track.Translations[lang])
+
+		if !slices.Contains(rec.track.ManualTranslations, lang) {
+			rec.track.ManualTranslations = append(rec.track.ManualTranslations, lang)
+		}
+	}
+
+	slices.Sort(rec.track.ManualTranslations)
+
+	rec.track.Version++
+	rec.track.UpdatedAt = time.Now().UTC()
+
+	return copyTrack(rec.track), nil
+}
+
+func (s *Storage) TracksByArtist(
+	_ context.Context,
+	artist string,
+	page models.PageQuery,
+) (*models.TrackPage, error) {
+	const op = "storage.memory.TracksByArtist"
+
+	var after pagination.Cursor
+	if page.Cursor != "" {
+		cursor, err := pagination.Decode(page.Cursor)
+		if err != nil || cursor.Sort != string(page.Sort) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrInvalidCursor)
+		}
+
+		after = cursor
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	artist = identity.Normalize(artist)
+
+	var records []*record
+	for _, rec := range s.byKey {
+		if identity.Normalize(rec.track.Artist) == artist {
+			records = append(records, rec)
+		}
+	}
+
+	less := func(rec *record, c pagination.Cursor) int {
+		if page.Sort == models.SortByCreatedAt {
+			if n := rec.track.CreatedAt.Compare(c.CreatedAt); n != 0 {
+				return n
+			}
+		} else if n := strings.Compare(identity.Normalize(rec.track.Title), c.Title); n != 0 {
+			return n
+		}
+
+		return int(rec.id - c.ID)
+	}
+
+	cursorOf := func(rec *record) pagination.Cursor {
+		return pagination.Cursor{
+			Sort:      string(page.Sort),
+			Title:     identity.Normalize(rec.track.Title),
+			CreatedAt: rec.track.CreatedAt,
+			ID:        rec.id,
+		}
+	}
+
+	slices.SortFunc(records, func(a, b *record) int {
+		return less(a, cursorOf(b))
+	})
+
+	if page.Cursor != "" {
+		records = slices.DeleteFunc(records, func(rec *record) bool {
+			return less(rec, after) <= 0
+		})
+	}
+
+	if len(records) == 0 && page.Cursor == "" {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrArtistTracksNotFound)
+	}
+
+	result := &models.TrackPage{}
+
+	if len(records) > page.Limit {
+		records = records[:page.Limit]
+		result.NextCursor = pagination.Encode(cursorOf(records[len(records)-1]))
+	}
+
+	for _, rec := range records {
+		result.Tracks = append(result.Tracks, copyTrack(rec.track))
+	}
+
+	return result, nil
+}
+
+func (s *Storage) DeleteTrack(_ context.Context, uuid string) (*models.Track, error) {
+	const op = "storage.memory.DeleteTrack"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	rec, ok := s.byUUID[uuid]
+	if !ok {
+		return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	delete(s.byUUID, uuid)
+	delete(s.byKey, identity.TrackKey(rec.track.Artist, rec.track.Title))
+
+	return &models.Track{
+		UUID:   rec.track.UUID,
+		Artist: rec.track.Artist,
+		Title:  rec.track.Title,
+	}, nil
+}
+
+// SearchTracks approximates the Postgres full-text search: every query word
+// must occur in the lyrics or in one translation, negated words ("-word")
+// must not.
+func (s *Storage) SearchTracks(
+	_ context.Context,
+	query models.SearchQuery,
+) ([]*models.SearchHit, error) {
+	include, exclude := parseQuery(query.Query)
+	if len(include) == 0 {
+		return nil, nil
+	}
+
+	artist := identity.Normalize(query.Artist)
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	type scored struct {
+		id  int64
+		hit *models.SearchHit
+	}
+
+	var results []scored
+	for _, rec := range s.byKey {
+		track := rec.track
+
+		if artist != "" && identity.Normalize(track.Artist) != artist {
+			continue
+		}
+
+		hit := &models.SearchHit{
+			UUID:   track.UUID,
+			Artist: track.Artist,
+			Title:  track.Title,
+		}
+
+		lyricsRank, lyricsMatch := matchDocument(track.Lyrics, include, exclude)
+		if lyricsMatch {
+			hit.Rank += lyricsRank
+			hit.Lyrics = highlightLines(track.Lyrics, include, exclude)
+		}
+
+		var bestRank float64
+		for _, lang := range slices.Sorted(maps.Keys(track.Translations)) {
+			lines := track.Translations[lang]
+
+			rank, ok := matchDocument(lines, include, exclude)
+			if !ok || rank <= bestRank {
+				continue
+			}
+
+			bestRank = rank
+			hit.Language = lang
+			hit.Translation = highlightLines(lines, include, exclude)
+		}
+
+		if !lyricsMatch && hit.Language == "" {
+			continue
+		}
+
+		hit.Rank += bestRank * translationWeight
+
+		if hit.Lyrics == nil {
+			hit.Lyrics = []string{}
+		}
+
+		if hit.Translation == nil {
+			hit.Translation = []string{}
+		}
+
+		results = append(results, scored{id: rec.id, hit: hit})
+	}
+
+	slices.SortFunc(results, func(a, b scored) int {
+		if a.hit.Rank != b.hit.Rank {
+			if a.hit.Rank > b.hit.Rank {
+				return -1
+			}
+
+			return 1
+		}
+
+		return int(a.id - b.id)
+	})
+
+	if len(results) > query.Limit {
+		results = results[:query.Limit]
+	}
+
+	hits := make([]*models.SearchHit, 0, len(results))
+	for _, r := range results {
+		hits = append(hits, r.hit)
+	}
+
+	return hits, nil
+}
+
+func (s *Storage) Ping(_ context.Context) error {
+	return nil
+}
+
+func (s *Storage) Close(_ context.Context) error {
+	return nil
+}
+
+func parseQuery(query string) (include, exclude []string) {
+	for _, field := range strings.Fields(query) {
+		negated := strings.HasPrefix(field, "-")
+
+		for _, word := range words(field) {
+			if word == "or" {
+				continue
+			}
+
+			if negated {
+				exclude = append(exclude, word)
+			} else {
+				include = append(include, word)
+			}
+		}
+	}
+
+	return include, exclude
+}
+
+// matchDocument reports whether lines as a whole contain every included
+// word and no excluded one, ranked by the number of occurrences.
+func matchDocument(lines, include, exclude []string) (float64, bool) {
+	counts := make(map[string]int)
+	for _, line := range lines {
+		for _, word := range words(line) {
+			counts[word]++
+		}
+	}
+
+	var rank float64
+	for _, word := range include {
+		if counts[word] == 0 {
+			return 0, false
+		}
+
+		rank += float64(counts[word])
+	}
+
+	for _, word := range exclude {
+		if counts[word] > 0 {
+			return 0, false
+		}
+	}
+
+	return rank / float64(len(include)), true
+}
+
+func highlightLines(lines, include, exclude []string) []string {
+	result := []string{}
+
+	for _, line := range lines {
+		if _, ok := matchDocument([]string{line}, include, exclude); !ok {
+			continue
+		}
+
+		result = append(result, highlight(line, include))
+	}
+
+	return result
+}
+
+func highlight(line string, include []string) string {
+	var b strings.Builder
+
+	for _, token := range tokens(line) {
+		if token.word && slices.Contains(include, strings.ToLower(token.text)) {
+			b.WriteString(markStart + token.text + markEnd)
+
+			continue
+		}
+
+		b.WriteString(token.text)
+	}
+
+	return b.String()
+}
+
+type token struct {
+	text string
+	word bool
+}
+
+// tokens splits s into words and the separators between them.
+func tokens(s string) []token {
+	var (
+		result  []token
+		current []rune
+		inWord  bool
+	)
+
+	flush := func() {
+		if len(current) > 0 {
+			result = append(result, token{text: string(current), word: inWord})
+			current = current[:0]
+		}
+	}
+
+	for _, r := range s {
+		isWord := isWordRune(r)
+		if isWord != inWord {
+			flush()
+			inWord = isWord
+		}
+
+		current = append(current, r)
+	}
+
+	flush()
+
+	return result
+}
+
+func words(s string) []string {
+	var result []string
+
+	for _, token := range tokens(s) {
+		if token.word {
+			result = append(result, strings.ToLower(token.text))
+		}
+	}
+
+	return result
+}
+
+func copyTrack(track *models.Track) *models.Track {
+	copied := *track
+	copied.Lyrics = slices.Clone(track.Lyrics)
+	copied.Synced = slices.Clone(track.Synced)
+	copied.ManualTranslations = slices.Clone(track.ManualTranslations)
+
+	if track.Translations != nil {
+		copied.Translations = make(map[string][]string, len(track.Translations))
+
+		for lang, lines := range track.Translations {
+			copied.Translations[lang] = slices.Clone(lines)
+		}
+	}
+
+	return &copied
+}
+
+func isWordRune(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
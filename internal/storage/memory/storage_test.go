@@ -0,0 +1,105 @@
+package memory_test
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/memory"
+)
+
+func TestTracksByArtistMatchesNormalizedArtist(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	for _, track := range []*models.Track{
+		{Artist: "AC/DC", Title: "Thunderstruck", Lyrics: []string{"thunder"}},
+		{Artist: "ac/dc", Title: "Highway to Hell", Lyrics: []string{"highway"}},
+		{Artist: "ACxDC", Title: "Imposter", Lyrics: []string{"thunder"}},
+		{Artist: "100%", Title: "Percent", Lyrics: []string{"thunder"}},
+	} {
+		if _, err := s.SaveTrack(ctx, track); err != nil {
+			t.Fatalf("SaveTrack() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		artist     string
+		want       int
+		wantSearch int
+	}{
+		{artist: "AC/DC", want: 2, wantSearch: 1},
+		{artist: "  ac/dc ", want: 2, wantSearch: 1},
+		{artist: "AC_DC", want: 0, wantSearch: 0},
+		{artist: "1%", want: 0, wantSearch: 0},
+		{artist: "%", want: 0, wantSearch: 0},
+		{artist: "100%", want: 1, wantSearch: 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.artist, func(t *testing.T) {
+			page, err := s.TracksByArtist(ctx, tt.artist, models.PageQuery{Limit: 10, Sort: models.SortByTitle})
+			if tt.want == 0 {
+				if !errors.Is(err, storage.ErrArtistTracksNotFound) {
+					t.Errorf("TracksByArtist() error = %v, want %v", err, storage.ErrArtistTracksNotFound)
+				}
+			} else if err != nil {
+				t.Fatalf("TracksByArtist() error = %v", err)
+			} else if len(page.Tracks) != tt.want {
+				t.Errorf("TracksByArtist() returned %d tracks, want %d", len(page.Tracks), tt.want)
+			}
+
+			hits, err := s.SearchTracks(ctx, models.SearchQuery{Query: "thunder", Artist: tt.artist, Limit: 10})
+			if err != nil {
+				t.Fatalf("SearchTracks() error = %v", err)
+			}
+
+			if len(hits) != tt.wantSearch {
+				t.Errorf("SearchTracks() returned %d hits, want %d", len(hits), tt.wantSearch)
+			}
+		})
+	}
+}
+
+func TestTracksByArtistSortsByNormalizedTitle(t *testing.T) {
+	ctx := context.Background()
+	s := memory.New()
+
+	for _, title := range []string{"Zebra", "apple", "Émile", "beta", "Alpha"} {
+		if _, err := s.SaveTrack(ctx, &models.Track{Artist: "Artist", Title: title, Lyrics: []string{"la"}}); err != nil {
+			t.Fatalf("SaveTrack() error = %v", err)
+		}
+	}
+
+	// Case-folded titles in byte order, as title_key sorts in Postgres.
+	want := []string{"Alpha", "apple", "beta", "Zebra", "Émile"}
+
+	var (
+		titles []string
+		cursor string
+	)
+
+	for {
+		page, err := s.TracksByArtist(ctx, "artist", models.PageQuery{Limit: 2, Cursor: cursor, Sort: models.SortByTitle})
+		if err != nil {
+			t.Fatalf("TracksByArtist() error = %v", err)
+		}
+
+		for _, track := range page.Tracks {
+			titles = append(titles, track.Title)
+		}
+
+		if page.NextCursor == "" {
+			break
+		}
+
+		cursor = page.NextCursor
+	}
+
+	if !slices.Equal(titles, want) {
+		t.Errorf("TracksByArtist() titles = %q, want %q", titles, want)
+	}
+}
@@ -0,0 +1,166 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/tracing"
+	"lyrics-library/internal/storage"
+)
+
+const (
+	apiKeyColumns = `id, name, prefix, scopes, created_at, revoked_at`
+)
+
+func (s *Storage) CreateAPIKey(ctx context.Context, key *models.APIKey, hash string) error {
+	const op = "storage.postgres.CreateAPIKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("CreateAPIKey", time.Now())
+
+	row := s.db.QueryRowContext(ctx, `
+		INSERT INTO api_keys (name, prefix, key_hash, scopes)
+		VALUES ($1, $2, $3, $4)
+		RETURNING id, created_at
+	`, key.Name, key.Prefix, hash, pq.Array(key.Scopes))
+
+	if err := row.Scan(&key.ID, &key.CreatedAt); err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) APIKeyByHash(ctx context.Context, hash string) (*models.APIKey, error) {
+	const op = "storage.postgres.APIKeyByHash"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("APIKeyByHash", time.Now())
+
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		WHERE key_hash = $1
+	`, hash)
+
+	key, err := scanAPIKey(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+		}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return key, nil
+}
+
+func (s *Storage) APIKeys(ctx context.Context) ([]*models.APIKey, error) {
+	const op = "storage.postgres.APIKeys"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("APIKeys", time.Now())
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT `+apiKeyColumns+` FROM api_keys
+		ORDER BY created_at
+	`)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var keys []*models.APIKey
+	for rows.Next() {
+		key, err := scanAPIKey(rows)
+		if err != nil {
+			tracing.Fail(span, err)
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		keys = append(keys, key)
+	}
+
+	if err := rows.Err(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return keys, nil
+}
+
+// RevokeAPIKey marks an active key as revoked.
+func (s *Storage) RevokeAPIKey(ctx context.Context, id string) error {
+	const op = "storage.postgres.RevokeAPIKey"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("RevokeAPIKey", time.Now())
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE api_keys SET revoked_at = now()
+		WHERE id::text = $1 AND revoked_at IS NULL
+	`, id)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrAPIKeyNotFound)
+	}
+
+	return nil
+}
+
+func scanAPIKey(row rowScanner) (*models.APIKey, error) {
+	var (
+		key       models.APIKey
+		scopes    []string
+		revokedAt sql.NullTime
+	)
+
+	err := row.Scan(&key.ID, &key.Name, &key.Prefix, pq.Array(&scopes),
+		&key.CreatedAt, &revokedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scope := range scopes {
+		key.Scopes = append(key.Scopes, models.Scope(scope))
+	}
+
+	if revokedAt.Valid {
+		key.RevokedAt = &revokedAt.Time
+	}
+
+	return &key, nil
+}
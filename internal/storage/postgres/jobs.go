@@ -0,0 +1,234 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+
+	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/tracing"
+	"lyrics-library/internal/storage"
+)
+
+const (
+	jobColumns = `id, status, artist, title, target_langs, COALESCE(track_uuid::text, ''),
+		error, attempts, client_id, created_at, updated_at`
+)
+
+func (s *Storage) CreateJob(ctx context.Context, job *models.Job) error {
+	const op = "storage.postgres.CreateJob"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("CreateJob", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO jobs (status, artist, title, target_langs, client_id)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, models.JobPending, job.Artist, job.Title, pq.Array(job.TargetLangs), job.ClientID)
+
+	if err := row.Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt); err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	job.Status = models.JobPending
+
+	return tx.Commit()
+}
+
+func (s *Storage) Job(ctx context.Context, id string) (*models.Job, error) {
+	const op = "storage.postgres.Job"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("Job", time.Now())
+
+	row := s.db.QueryRowContext(ctx, `
+		SELECT `+jobColumns+` FROM jobs
+		WHERE id = $1
+	`, id)
+
+	job, err := scanJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrJobNotFound)
+		}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+// ClaimJob marks the oldest pending job as running and returns it. Jobs
+// already attempted wait retryDelay since their last update. Locked rows
+// are skipped, so several instances can poll the same table. It is not
+// traced: idle workers poll it every interval.
+func (s *Storage) ClaimJob(ctx context.Context, retryDelay time.Duration) (*models.Job, error) {
+	const op = "storage.postgres.ClaimJob"
+
+	defer s.metrics.ObserveQuery("ClaimJob", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	row := tx.QueryRowContext(ctx, `
+		UPDATE jobs SET status = $1, attempts = attempts + 1, updated_at = now()
+		WHERE id = (
+			SELECT id FROM jobs
+			WHERE status = $2
+				AND (attempts = 0 OR updated_at < now() - make_interval(secs => $3))
+			ORDER BY created_at
+			FOR UPDATE SKIP LOCKED
+			LIMIT 1
+		)
+		RETURNING `+jobColumns,
+		models.JobRunning, models.JobPending, retryDelay.Seconds())
+
+	job, err := scanJob(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, storage.ErrNoPendingJobs
+		}
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return job, nil
+}
+
+func (s *Storage) UpdateJob(ctx context.Context, job *models.Job) error {
+	const op = "storage.postgres.UpdateJob"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("UpdateJob", time.Now())
+
+	var trackUUID sql.NullString
+	if job.TrackUUID != "" {
+		trackUUID = sql.NullString{String: job.TrackUUID, Valid: true}
+	}
+
+	row := s.db.QueryRowContext(ctx, `
+		UPDATE jobs SET status = $2, track_uuid = $3, error = $4, updated_at = now()
+		WHERE id = $1
+		RETURNING updated_at
+	`, job.ID, job.Status, trackUUID, job.Error)
+
+	if err := row.Scan(&job.UpdatedAt); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("%s: %w", op, storage.ErrJobNotFound)
+		}
+
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// RequeueStaleJobs returns jobs that have been running for longer than
+// olderThan back to pending. Such jobs belong to a worker that died.
+func (s *Storage) RequeueStaleJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	const op = "storage.postgres.RequeueStaleJobs"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("RequeueStaleJobs", time.Now())
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE jobs SET status = $1, updated_at = now()
+		WHERE status = $2 AND updated_at < now() - make_interval(secs => $3)
+	`, models.JobPending, models.JobRunning, olderThan.Seconds())
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	requeued, err := res.RowsAffected()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return requeued, nil
+}
+
+// DeleteFinishedJobs deletes succeeded and failed jobs last updated more
+// than olderThan ago.
+func (s *Storage) DeleteFinishedJobs(ctx context.Context, olderThan time.Duration) (int64, error) {
+	const op = "storage.postgres.DeleteFinishedJobs"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("DeleteFinishedJobs", time.Now())
+
+	res, err := s.db.ExecContext(ctx, `
+		DELETE FROM jobs
+		WHERE status IN ($1, $2) AND updated_at < now() - make_interval(secs => $3)
+	`, models.JobSucceeded, models.JobFailed, olderThan.Seconds())
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	deleted, err := res.RowsAffected()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return deleted, nil
+}
+
+func scanJob(row rowScanner) (*models.Job, error) {
+	var (
+		job         models.Job
+		targetLangs []string
+	)
+
+	err := row.Scan(&job.ID, &job.Status, &job.Artist, &job.Title,
+		pq.Array(&targetLangs), &job.TrackUUID, &job.Error, &job.Attempts,
+		&job.ClientID, &job.CreatedAt, &job.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+
+	job.TargetLangs = targetLangs
+
+	return &job, nil
+}
@@ -3,20 +3,44 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/lib/pq"
 
 	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/identity"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/pagination"
+	"lyrics-library/internal/lib/tracing"
 	"lyrics-library/internal/storage"
 )
 
+const (
+	trackColumns = `uuid, artist, title, lyrics, lyrics_source, version, created_at, updated_at, synced_lyrics,
+		COALESCE((
+			SELECT json_object_agg(t.lang, t.lines)
+			FROM translations t WHERE t.song_id = songs.id
+		), '{}'),
+		ARRAY(
+			SELECT t.lang FROM translations t
+			WHERE t.song_id = songs.id AND t.manual
+			ORDER BY t.lang
+		)`
+
+	uniqueViolation = "23505"
+
+	headlineOptions = "StartSel=<mark>, StopSel=</mark>, HighlightAll=true"
+)
+
 type Storage struct {
-	db *sql.DB
+	db      *sql.DB
+	metrics *metrics.Metrics
 }
 
-func New(dbURL string) (*Storage, error) {
+func New(dbURL string, m *metrics.Metrics) (*Storage, error) {
 	const op = "storage.postgres.New"
 
 	db, err := sql.Open("postgres", dbURL)
@@ -28,143 +52,676 @@ func New(dbURL string) (*Storage, error) {
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &Storage{db: db}, nil
+	return &Storage{db: db, metrics: m}, nil
 }
 
-func (s *Storage) SaveTrack(ctx context.Context, track *models.Track) error {
+// SaveTrack inserts the track unless a track with the same identity already
+// exists. In the latter case track is overwritten with the stored one and
+// created is false.
+func (s *Storage) SaveTrack(ctx context.Context, track *models.Track) (bool, error) {
 	const op = "storage.postgres.Save"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("SaveTrack", time.Now())
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		tracing.Fail(span, err)
+
+		return false, fmt.Errorf("%s: %w", op, err)
 	}
 	defer tx.Rollback()
 
-	_, err = tx.ExecContext(ctx, `
-		INSERT INTO songs (artist, title, lyrics, translation)
-		VALUES ($1, $2, $3, $4)
-	`, track.Artist, track.Title, pq.Array(track.Lyrics), pq.Array(track.Translation))
+	key := identity.TrackKey(track.Artist, track.Title)
+
+	var id int64
+
+	row := tx.QueryRowContext(ctx, `
+		INSERT INTO songs (identity_key, artist, title, lyrics, lyrics_source)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (identity_key) DO NOTHING
+		RETURNING id, uuid, version, created_at, updated_at
+	`, key, track.Artist, track.Title, pq.Array(track.Lyrics), track.Source)
+
+	err = row.Scan(&id, &track.UUID, &track.Version, &track.CreatedAt, &track.UpdatedAt)
+	if err == nil {
+		for lang, lines := range track.Translations {
+			_, err := tx.ExecContext(ctx, `
+				INSERT INTO translations (song_id, lang, lines)
+				VALUES ($1, $2, $3)
+			`, id, lang, pq.Array(lines))
+			if err != nil {
+				tracing.Fail(span, err)
+
+				return false, fmt.Errorf("%s: %w", op, err)
+			}
+		}
+
+		return true, tx.Commit()
+	}
+
+	if !errors.Is(err, sql.ErrNoRows) {
+		tracing.Fail(span, err)
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	row = tx.QueryRowContext(ctx, `
+		SELECT `+trackColumns+` FROM songs
+		WHERE identity_key = $1
+	`, key)
+
+	existing, err := scanTrack(row)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		tracing.Fail(span, err)
+
+		return false, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return tx.Commit()
+	*track = *existing
+
+	return false, tx.Commit()
 }
 
 func (s *Storage) Track(ctx context.Context, artist, title string) (*models.Track, error) {
 	const op = "storage.postgres.TrackInfo"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("Track", time.Now())
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer tx.Rollback()
 
 	row := tx.QueryRowContext(ctx, `
-		SELECT artist, title, lyrics, translation FROM songs 
-		WHERE artist ILIKE $1 AND title ILIKE $2
-	`, artist, title)
-
-	var lyrics, translation []string
+		SELECT `+trackColumns+` FROM songs
+		WHERE identity_key = $1
+	`, identity.TrackKey(artist, title))
 
-	err = row.Scan(&artist, &title, pq.Array(&lyrics), pq.Array(&translation))
+	track, err := scanTrack(row)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return nil, storage.ErrTrackNotFound
 		}
 
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err := tx.Commit(); err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return &models.Track{
-		Artist:      artist,
-		Title:       title,
-		Lyrics:      lyrics,
-		Translation: translation,
-	}, nil
+	return track, nil
 }
 
-func (s *Storage) TracksByArtist(ctx context.Context, artist string) ([]*models.Track, error) {
-	const op = "storage.postgres.TracksByArtist"
+func (s *Storage) TrackByUUID(ctx context.Context, uuid string) (*models.Track, error) {
+	const op = "storage.postgres.TrackByUUID"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("TrackByUUID", time.Now())
 
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer tx.Rollback()
 
-	rows, err := tx.QueryContext(ctx, `
-		SELECT artist, title, lyrics, translation
-		FROM songs WHERE artist ILIKE $1
-	`, artist)
+	row := tx.QueryRowContext(ctx, `
+		SELECT `+trackColumns+` FROM songs
+		WHERE uuid = $1
+	`, uuid)
+
+	track, err := scanTrack(row)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+		}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return track, nil
+}
+
+func (s *Storage) SaveTranslation(ctx context.Context, uuid, lang string, lines []string) error {
+	const op = "storage.postgres.SaveTranslation"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("SaveTranslation", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		INSERT INTO translations (song_id, lang, lines)
+		SELECT id, $2, $3 FROM songs WHERE uuid = $1
+		ON CONFLICT (song_id, lang) DO NOTHING
+	`, uuid, lang, pq.Array(lines))
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		var exists bool
+
+		err := tx.QueryRowContext(ctx, `
+			SELECT EXISTS (SELECT 1 FROM songs WHERE uuid = $1)
+		`, uuid).Scan(&exists)
+		if err != nil {
+			tracing.Fail(span, err)
+
+			return fmt.Errorf("%s: %w", op, err)
+		}
+
+		if !exists {
+			return fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+		}
+
+		return tx.Commit()
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE songs SET version = version + 1, updated_at = now()
+		WHERE uuid = $1
+	`, uuid)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return tx.Commit()
+}
+
+// SaveSyncedLyrics replaces the synced lyrics of the track.
+func (s *Storage) SaveSyncedLyrics(ctx context.Context, uuid string, lines []models.SyncedLine) error {
+	const op = "storage.postgres.SaveSyncedLyrics"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("SaveSyncedLyrics", time.Now())
+
+	synced, err := json.Marshal(lines)
 	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `
+		UPDATE songs SET synced_lyrics = $2, version = version + 1, updated_at = now()
+		WHERE uuid = $1
+	`, uuid, synced)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+	}
+
+	return nil
+}
+
+func (s *Storage) TracksByArtist(
+	ctx context.Context,
+	artist string,
+	page models.PageQuery,
+) (*models.TrackPage, error) {
+	const op = "storage.postgres.TracksByArtist"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("TracksByArtist", time.Now())
+
+	after, err := decodeCursor(page)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// title_key is the normalized title in byte order, the same order as
+	// the in-memory storage.
+	orderBy := "title_key"
+	if page.Sort == models.SortByCreatedAt {
+		orderBy = "created_at"
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
+
+	// One extra row is fetched to find out whether there is a next page.
+	query := fmt.Sprintf(`
+		SELECT id, `+trackColumns+`
+		FROM songs
+		WHERE artist_key = $1 AND ($2 OR (%[1]s, id) > ($3, $4))
+		ORDER BY %[1]s, id
+		LIMIT $5
+	`, orderBy)
+
+	var afterValue any = after.Title
+	if page.Sort == models.SortByCreatedAt {
+		afterValue = after.CreatedAt
+	}
 
-	var tracks []*models.Track
+	rows, err := tx.QueryContext(ctx, query,
+		identity.Normalize(artist), page.Cursor == "", afterValue, after.ID, page.Limit+1)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
 
 	var (
-		title       string
-		lyrics      []string
-		translation []string
+		tracks     []*models.Track
+		last       pagination.Cursor
+		nextCursor string
 	)
 	for rows.Next() {
-		err := rows.Scan(&artist, &title, pq.Array(&lyrics), pq.Array(&translation))
+		var id int64
+
+		track, err := scanTrack(rows, &id)
 		if err != nil {
+			tracing.Fail(span, err)
+
 			return nil, fmt.Errorf("%s: %w", op, err)
 		}
 
-		tracks = append(tracks, &models.Track{
-			Artist:      artist,
-			Title:       title,
-			Lyrics:      lyrics,
-			Translation: translation,
-		})
+		if len(tracks) == page.Limit {
+			nextCursor = pagination.Encode(last)
+
+			break
+		}
+
+		tracks = append(tracks, track)
+		last = pagination.Cursor{
+			Sort:      string(page.Sort),
+			Title:     identity.Normalize(track.Title),
+			CreatedAt: track.CreatedAt,
+			ID:        id,
+		}
 	}
 
 	if err := rows.Err(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	// The extra row may be left unread.
+	if err := rows.Close(); err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	if err := tx.Commit(); err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	if len(tracks) == 0 {
+	if len(tracks) == 0 && page.Cursor == "" {
 		return nil, fmt.Errorf("%s: %w", op, storage.ErrArtistTracksNotFound)
 	}
 
-	return tracks, nil
+	return &models.TrackPage{Tracks: tracks, NextCursor: nextCursor}, nil
 }
 
-func (s *Storage) DeleteTrack(ctx context.Context, uuid string) error {
+func (s *Storage) SearchTracks(
+	ctx context.Context,
+	query models.SearchQuery,
+) ([]*models.SearchHit, error) {
+	const op = "storage.postgres.SearchTracks"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("SearchTracks", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer tx.Rollback()
+
+	// Only the best matching translation of each song is returned.
+	rows, err := tx.QueryContext(ctx, `
+		SELECT s.uuid, s.artist, s.title,
+			ts_rank(s.search_vector, q) + COALESCE(t.rank, 0) AS rank,
+			ARRAY(
+				SELECT ts_headline('simple', l.line, q, $4)
+				FROM unnest(s.lyrics) WITH ORDINALITY AS l(line, n)
+				WHERE to_tsvector('simple', l.line) @@ q
+				ORDER BY l.n
+			),
+			COALESCE(t.lang, ''),
+			COALESCE(t.lines, '{}')
+		FROM songs s
+		CROSS JOIN websearch_to_tsquery('simple', $1) AS q
+		LEFT JOIN LATERAL (
+			SELECT tr.lang, ts_rank(tr.search_vector, q) AS rank,
+				ARRAY(
+					SELECT ts_headline('simple', l.line, q, $4)
+					FROM unnest(tr.lines) WITH ORDINALITY AS l(line, n)
+					WHERE to_tsvector('simple', l.line) @@ q
+					ORDER BY l.n
+				) AS lines
+			FROM translations tr
+			WHERE tr.song_id = s.id AND tr.search_vector @@ q
+			ORDER BY rank DESC, tr.lang
+			LIMIT 1
+		) t ON true
+		WHERE (s.search_vector @@ q OR t.lang IS NOT NULL)
+			AND ($2 = '' OR s.artist_key = $2)
+		ORDER BY rank DESC, s.id
+		LIMIT $3
+	`, query.Query, identity.Normalize(query.Artist), query.Limit, headlineOptions)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+	defer rows.Close()
+
+	var hits []*models.SearchHit
+	for rows.Next() {
+		var (
+			hit         models.SearchHit
+			lyrics      []string
+			translation []string
+		)
+
+		err := rows.Scan(&hit.UUID, &hit.Artist, &hit.Title, &hit.Rank,
+			pq.Array(&lyrics), &hit.Language, pq.Array(&translation))
+		if err != nil {
+			tracing.Fail(span, err)
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+
+		hit.Lyrics = lyrics
+		hit.Translation = translation
+
+		hits = append(hits, &hit)
+	}
+
+	if err := rows.Err(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return hits, nil
+}
+
+// DeleteTrack deletes the track and returns what it was, so callers can
+// invalidate anything derived from it.
+func (s *Storage) DeleteTrack(ctx context.Context, uuid string) (*models.Track, error) {
 	const op = "storage.postgres.DeleteTrack"
 
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("DeleteTrack", time.Now())
+
 	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 	defer tx.Rollback()
 
-	res, err := tx.ExecContext(ctx, `DELETE FROM songs WHERE uuid = $1`, uuid)
+	var track models.Track
+
+	err = tx.QueryRowContext(ctx, `
+		DELETE FROM songs WHERE uuid = $1
+		RETURNING uuid, artist, title
+	`, uuid).Scan(&track.UUID, &track.Artist, &track.Title)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+		}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	rowsAffected, err := res.RowsAffected()
+	if err := tx.Commit(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &track, nil
+}
+
+// UpdateTrack stores the artist, title and lyrics of track and the
+// translations into langs, which are marked as manual. It fails with
+// storage.ErrVersionConflict unless the stored track is still at version.
+func (s *Storage) UpdateTrack(
+	ctx context.Context,
+	track *models.Track,
+	version int64,
+	langs []string,
+) (*models.Track, error) {
+	const op = "storage.postgres.UpdateTrack"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	defer s.metrics.ObserveQuery("UpdateTrack", time.Now())
+
+	tx, err := s.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("%s: %w", op, err)
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
+	defer tx.Rollback()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("%s: %w", op, storage.ErrInvalidUUID)
+	var id int64
+
+	err = tx.QueryRowContext(ctx, `
+		UPDATE songs
+		SET identity_key = $3, artist = $4, title = $5, lyrics = $6,
+			version = version + 1, updated_at = now()
+		WHERE uuid = $1 AND version = $2
+		RETURNING id
+	`, track.UUID, version, identity.TrackKey(track.Artist, track.Title),
+		track.Artist, track.Title, pq.Array(track.Lyrics)).Scan(&id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			var exists bool
+
+			err := tx.QueryRowContext(ctx, `
+				SELECT EXISTS (SELECT 1 FROM songs WHERE uuid = $1)
+			`, track.UUID).Scan(&exists)
+			if err != nil {
+				tracing.Fail(span, err)
+
+				return nil, fmt.Errorf("%s: %w", op, err)
+			}
+
+			if !exists {
+				return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotFound)
+			}
+
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrVersionConflict)
+		}
+
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == uniqueViolation {
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackExists)
+		}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return tx.Commit()
+	for _, lang := range langs {
+		_, err := tx.ExecContext(ctx, `
+			UPDATE translations SET lines = $3, manual = true, updated_at = now()
+			WHERE song_id = $1 AND lang = $2
+		`, id, lang, pq.Array(track.Translations[lang]))
+		if err != nil {
+			tracing.Fail(span, err)
+
+			return nil, fmt.Errorf("%s: %w", op, err)
+		}
+	}
+
+	updated, err := scanTrack(tx.QueryRowContext(ctx, `
+		SELECT `+trackColumns+` FROM songs
+		WHERE id = $1
+	`, id))
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return updated, nil
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+// scanTrack reads a row selected with trackColumns, optionally preceded
+// by the extra destinations in prefix.
+func scanTrack(row rowScanner, prefix ...any) (*models.Track, error) {
+	var (
+		track        models.Track
+		lyrics       []string
+		synced       []byte
+		translations []byte
+		manual       []string
+	)
+
+	dest := append(prefix,
+		&track.UUID,
+		&track.Artist,
+		&track.Title,
+		pq.Array(&lyrics),
+		&track.Source,
+		&track.Version,
+		&track.CreatedAt,
+		&track.UpdatedAt,
+		&synced,
+		&translations,
+		pq.Array(&manual),
+	)
+
+	if err := row.Scan(dest...); err != nil {
+		return nil, err
+	}
+
+	if err := json.Unmarshal(translations, &track.Translations); err != nil {
+		return nil, err
+	}
+
+	if synced != nil {
+		if err := json.Unmarshal(synced, &track.Synced); err != nil {
+			return nil, err
+		}
+	}
+
+	track.Lyrics = lyrics
+
+	if len(manual) > 0 {
+		track.ManualTranslations = manual
+	}
+
+	return &track, nil
+}
+
+func decodeCursor(page models.PageQuery) (pagination.Cursor, error) {
+	if page.Cursor == "" {
+		return pagination.Cursor{}, nil
+	}
+
+	cursor, err := pagination.Decode(page.Cursor)
+	if err != nil || cursor.Sort != string(page.Sort) {
+		return pagination.Cursor{}, storage.ErrInvalidCursor
+	}
+
+	return cursor, nil
 }
 
 func (s *Storage) Ping(ctx context.Context) error {
@@ -0,0 +1,112 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"lyrics-library/internal/lib/tracing"
+)
+
+const translationQuotaTTL = 48 * time.Hour
+
+// tokenBucket refills the bucket by the time elapsed since the last call
+// and takes a token from it. The Redis clock is used so every instance
+// sees the same time. It returns whether a token was taken and, if not,
+// the milliseconds until the next one.
+var tokenBucket = redis.NewScript(`
+local rate = tonumber(ARGV[1])
+local burst = tonumber(ARGV[2])
+
+local time = redis.call('TIME')
+local now = tonumber(time[1]) * 1000 + math.floor(tonumber(time[2]) / 1000)
+
+local state = redis.call('HMGET', KEYS[1], 'tokens', 'ts')
+local tokens = tonumber(state[1]) or burst
+local ts = tonumber(state[2]) or now
+
+tokens = math.min(burst, tokens + math.max(now - ts, 0) / 1000 * rate)
+
+local allowed = 0
+local wait = 0
+if tokens >= 1 then
+	tokens = tokens - 1
+	allowed = 1
+else
+	wait = math.ceil((1 - tokens) / rate * 1000)
+end
+
+redis.call('HSET', KEYS[1], 'tokens', tostring(tokens), 'ts', now)
+redis.call('PEXPIRE', KEYS[1], math.ceil(burst / rate * 1000) + 1000)
+
+return {allowed, wait}
+`)
+
+// chargeQuota adds ARGV[1] to the counter unless that exceeds ARGV[2].
+var chargeQuota = redis.NewScript(`
+local used = redis.call('INCRBY', KEYS[1], ARGV[1])
+if used == tonumber(ARGV[1]) then
+	redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+
+if used > tonumber(ARGV[2]) then
+	redis.call('DECRBY', KEYS[1], ARGV[1])
+	return 0
+end
+
+return 1
+`)
+
+func (s *Storage) AllowRequest(
+	ctx context.Context,
+	key string,
+	rate float64,
+	burst int,
+) (bool, time.Duration, error) {
+	const op = "storage.redis.AllowRequest"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	result, err := tokenBucket.Run(ctx, s.db, []string{generateRateLimitKey(key)}, rate, burst).Int64Slice()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return false, 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return result[0] == 1, time.Duration(result[1]) * time.Millisecond, nil
+}
+
+func (s *Storage) ChargeTranslationQuota(
+	ctx context.Context,
+	client string,
+	day time.Time,
+	chars, limit int64,
+) (bool, error) {
+	const op = "storage.redis.ChargeTranslationQuota"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	key := generateTranslationQuotaKey(client, day)
+
+	charged, err := chargeQuota.Run(ctx, s.db, []string{key}, chars, limit, translationQuotaTTL.Milliseconds()).Int()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return false, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return charged == 1, nil
+}
+
+func generateRateLimitKey(key string) string {
+	return fmt.Sprintf("rate_limit:%s", key)
+}
+
+func generateTranslationQuotaKey(client string, day time.Time) string {
+	return fmt.Sprintf("translation_quota:%s:%s", day.Format(time.DateOnly), client)
+}
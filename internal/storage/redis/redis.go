@@ -5,47 +5,178 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"time"
 
 	"github.com/redis/go-redis/v9"
 
 	"lyrics-library/internal/domain/models"
+	"lyrics-library/internal/lib/metrics"
+	"lyrics-library/internal/lib/tracing"
 	"lyrics-library/internal/storage"
+	"lyrics-library/internal/storage/cachekey"
 )
 
+// generationKey is bumped by every write-through and invalidation, fills
+// read before the bump are dropped.
+const generationKey = "cache_generation"
+
+// saveTrack writes the track to KEYS[2:] and bumps the generation in
+// KEYS[1], unless a newer version of the same track is already cached
+// there. ARGV holds the document, the TTL in milliseconds (0 for none),
+// the uuid and the version.
+var saveTrack = redis.NewScript(`
+for i = 2, #KEYS do
+	local cached = redis.call('GET', KEYS[i])
+	if cached then
+		local ok, track = pcall(cjson.decode, cached)
+		if ok and type(track) == 'table' and track.uuid == ARGV[3]
+			and (tonumber(track.version) or 0) > tonumber(ARGV[4]) then
+			return 0
+		end
+	end
+end
+
+redis.call('INCR', KEYS[1])
+
+for i = 2, #KEYS do
+	if tonumber(ARGV[2]) > 0 then
+		redis.call('SET', KEYS[i], ARGV[1], 'PX', ARGV[2])
+	else
+		redis.call('SET', KEYS[i], ARGV[1])
+	end
+end
+
+return 1
+`)
+
+// fillTrack writes the document in ARGV[2] to KEYS[2:] with the TTL in
+// ARGV[3] if the generation in KEYS[1] is still ARGV[1].
+var fillTrack = redis.NewScript(`
+if (tonumber(redis.call('GET', KEYS[1])) or 0) ~= tonumber(ARGV[1]) then
+	return 0
+end
+
+for i = 2, #KEYS do
+	if tonumber(ARGV[3]) > 0 then
+		redis.call('SET', KEYS[i], ARGV[2], 'PX', ARGV[3])
+	else
+		redis.call('SET', KEYS[i], ARGV[2])
+	end
+end
+
+return 1
+`)
+
+// fillArtistTracks sets the field ARGV[2] of the hash in KEYS[2] to
+// ARGV[3] if the generation in KEYS[1] is still ARGV[1]. The TTL in ARGV[4]
+// is set by the first page only.
+var fillArtistTracks = redis.NewScript(`
+if (tonumber(redis.call('GET', KEYS[1])) or 0) ~= tonumber(ARGV[1]) then
+	return 0
+end
+
+redis.call('HSET', KEYS[2], ARGV[2], ARGV[3])
+
+if tonumber(ARGV[4]) > 0 and redis.call('PTTL', KEYS[2]) < 0 then
+	redis.call('PEXPIRE', KEYS[2], ARGV[4])
+end
+
+return 1
+`)
+
 type Storage struct {
-	db *redis.Client
+	db              *redis.Client
+	trackTTL        time.Duration
+	artistTracksTTL time.Duration
+	metrics         *metrics.Metrics
 }
 
-func New(redisURL, password string) (*Storage, error) {
-	const op = "storage.redis.New"
-
+// New doesn't connect, connections are made on first use so the service
+// can start while Redis is down.
+func New(
+	redisURL, password string,
+	trackTTL, artistTracksTTL time.Duration,
+	m *metrics.Metrics,
+) *Storage {
 	db := redis.NewClient(&redis.Options{
 		Addr:     redisURL,
 		Password: password,
 		DB:       0,
 	})
 
-	if _, err := db.Ping(context.Background()).Result(); err != nil {
-		return nil, fmt.Errorf("%s: %w", op, err)
+	return &Storage{
+		db:              db,
+		trackTTL:        trackTTL,
+		artistTracksTTL: artistTracksTTL,
+		metrics:         m,
 	}
+}
 
-	return &Storage{
-		db: db,
-	}, nil
+// Generation returns the current cache generation, to be passed to the
+// fills of what is read from the database afterwards.
+func (s *Storage) Generation(ctx context.Context) (int64, error) {
+	const op = "storage.redis.Generation"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	generation, err := s.db.Get(ctx, generationKey).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		tracing.Fail(span, err)
+
+		return 0, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return generation, nil
 }
 
+// SaveTrack writes a track that has just been changed in the database. A
+// write that lost the race against a newer version of the track is
+// skipped.
 func (s *Storage) SaveTrack(ctx context.Context, track *models.Track) error {
 	const op = "storage.redis.SaveTrack"
 
-	key := generateTrackKey(track.Artist, track.Title)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	data, err := json.Marshal(track)
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	err = saveTrack.Run(ctx, s.db, append([]string{generationKey}, cachekey.TrackKeys(track)...),
+		data, s.trackTTL.Milliseconds(), track.UUID, track.Version).Err()
+	if err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+// FillTrack caches a track read from the database, unless the cache has
+// been written or invalidated since generation was taken.
+func (s *Storage) FillTrack(ctx context.Context, track *models.Track, generation int64) error {
+	const op = "storage.redis.FillTrack"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
 	data, err := json.Marshal(track)
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	err = s.db.Set(ctx, key, data, 0).Err()
+	err = fillTrack.Run(ctx, s.db, append([]string{generationKey}, cachekey.TrackKeys(track)...),
+		generation, data, s.trackTTL.Milliseconds()).Err()
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
@@ -55,62 +186,178 @@ func (s *Storage) SaveTrack(ctx context.Context, track *models.Track) error {
 func (s *Storage) Track(ctx context.Context, artist, title string) (*models.Track, error) {
 	const op = "storage.redis.GetTrack"
 
-	key := generateTrackKey(artist, title)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	key := cachekey.Track(artist, title)
 
 	data, err := s.db.Get(ctx, key).Bytes()
 	if err != nil {
 		if errors.Is(err, redis.Nil) {
+			s.metrics.ObserveCacheLookup(metrics.CacheTrack, false)
+
 			return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotCached)
 		}
 
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
+	s.metrics.ObserveCacheLookup(metrics.CacheTrack, true)
+
 	var track models.Track
 	if err := json.Unmarshal(data, &track); err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
 	return &track, err
 }
 
-func (s *Storage) SaveArtistTracks(ctx context.Context, artist string, tracks []*models.Track) error {
+func (s *Storage) DeleteTrack(ctx context.Context, track *models.Track) error {
+	const op = "storage.redis.DeleteTrack"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pipe := s.db.TxPipeline()
+
+	pipe.Del(ctx, cachekey.TrackKeys(track)...)
+	pipe.Incr(ctx, generationKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
+}
+
+func (s *Storage) TrackByUUID(ctx context.Context, uuid string) (*models.Track, error) {
+	const op = "storage.redis.TrackByUUID"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	data, err := s.db.Get(ctx, cachekey.TrackUUID(uuid)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			s.metrics.ObserveCacheLookup(metrics.CacheTrack, false)
+
+			return nil, fmt.Errorf("%s: %w", op, storage.ErrTrackNotCached)
+		}
+
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	s.metrics.ObserveCacheLookup(metrics.CacheTrack, true)
+
+	var track models.Track
+	if err := json.Unmarshal(data, &track); err != nil {
+		tracing.Fail(span, err)
+
+		return nil, fmt.Errorf("%s: %w", op, err)
+	}
+
+	return &track, nil
+}
+
+// SaveArtistTracks stores the page in a hash shared by all pages of the
+// artist, so DeleteArtistTracks can drop them at once. The TTL is set by
+// the first page and is not extended by later ones. The page is dropped if
+// the cache has been written or invalidated since generation was taken.
+func (s *Storage) SaveArtistTracks(
+	ctx context.Context,
+	artist string,
+	query models.PageQuery,
+	page *models.TrackPage,
+	generation int64,
+) error {
 	const op = "storage.redis.SaveArtistTracks"
 
-	key := generateArtistTracksKey(artist)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	key := cachekey.ArtistTracks(artist)
 
-	data, err := json.Marshal(tracks)
+	data, err := json.Marshal(page)
 	if err != nil {
+		tracing.Fail(span, err)
+
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
-	if err := s.db.Set(ctx, key, data, 0).Err(); err != nil {
+	err = fillArtistTracks.Run(ctx, s.db, []string{generationKey, key},
+		generation, cachekey.PageField(query), data, s.artistTracksTTL.Milliseconds()).Err()
+	if err != nil {
+		tracing.Fail(span, err)
+
 		return fmt.Errorf("%s: %w", op, err)
 	}
 
 	return nil
 }
 
-func (s *Storage) ArtistTracks(ctx context.Context, artist string) ([]*models.Track, error) {
+func (s *Storage) ArtistTracks(
+	ctx context.Context,
+	artist string,
+	query models.PageQuery,
+) (*models.TrackPage, error) {
 	const op = "storage.redis.GetArtistTracks"
 
-	key := generateArtistTracksKey(artist)
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
 
-	data, err := s.db.Get(ctx, key).Bytes()
+	key := cachekey.ArtistTracks(artist)
+
+	data, err := s.db.HGet(ctx, key, cachekey.PageField(query)).Bytes()
 	if err != nil {
-		if errors.Is(err, storage.ErrArtistTracksNotCached) {
+		if errors.Is(err, redis.Nil) {
+			s.metrics.ObserveCacheLookup(metrics.CacheArtistTracks, false)
+
 			return nil, fmt.Errorf("%s: %w", op, storage.ErrArtistTracksNotCached)
 		}
 
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	var tracks []*models.Track
-	if err := json.Unmarshal(data, &tracks); err != nil {
+	s.metrics.ObserveCacheLookup(metrics.CacheArtistTracks, true)
+
+	var page models.TrackPage
+	if err := json.Unmarshal(data, &page); err != nil {
+		tracing.Fail(span, err)
+
 		return nil, fmt.Errorf("%s: %w", op, err)
 	}
 
-	return tracks, nil
+	return &page, nil
+}
+
+func (s *Storage) DeleteArtistTracks(ctx context.Context, artist string) error {
+	const op = "storage.redis.DeleteArtistTracks"
+
+	ctx, span := tracing.Start(ctx, op)
+	defer span.End()
+
+	pipe := s.db.TxPipeline()
+
+	pipe.Del(ctx, cachekey.ArtistTracks(artist))
+	pipe.Incr(ctx, generationKey)
+
+	if _, err := pipe.Exec(ctx); err != nil {
+		tracing.Fail(span, err)
+
+		return fmt.Errorf("%s: %w", op, err)
+	}
+
+	return nil
 }
 
 func (s *Storage) Close(ctx context.Context) error {
@@ -124,11 +371,3 @@ func (s *Storage) Close(ctx context.Context) error {
 func (s *Storage) Ping(ctx context.Context) error {
 	return s.db.Ping(ctx).Err()
 }
-
-func generateArtistTracksKey(artist string) string {
-	return fmt.Sprintf("artist_tracks:%s", artist)
-}
-
-func generateTrackKey(artist, title string) string {
-	return fmt.Sprintf("track:%s:%s", artist, title)
-}
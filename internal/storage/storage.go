@@ -4,8 +4,13 @@ import "errors"
 
 var (
 	ErrTrackNotFound         = errors.New("track not found")
+	ErrTrackExists           = errors.New("track already exists")
+	ErrVersionConflict       = errors.New("track version conflict")
 	ErrArtistTracksNotFound  = errors.New("artist's tracks not found")
-	ErrInvalidUUID           = errors.New("invalid uuid")
 	ErrTrackNotCached        = errors.New("track not cached")
 	ErrArtistTracksNotCached = errors.New("artist's track not cached")
+	ErrInvalidCursor         = errors.New("invalid cursor")
+	ErrJobNotFound           = errors.New("job not found")
+	ErrNoPendingJobs         = errors.New("no pending jobs")
+	ErrAPIKeyNotFound        = errors.New("api key not found")
 )
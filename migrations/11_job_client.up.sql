@@ -0,0 +1 @@
+ALTER TABLE jobs ADD COLUMN IF NOT EXISTS client_id TEXT NOT NULL DEFAULT '';
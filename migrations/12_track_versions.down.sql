@@ -0,0 +1,3 @@
+ALTER TABLE translations DROP COLUMN IF EXISTS manual;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS version;
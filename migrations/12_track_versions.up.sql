@@ -0,0 +1,3 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS version BIGINT NOT NULL DEFAULT 1;
+
+ALTER TABLE translations ADD COLUMN IF NOT EXISTS manual BOOLEAN NOT NULL DEFAULT false;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_songs_artist_key_created_at_id;
+DROP INDEX IF EXISTS idx_songs_artist_key_title_id;
+
+CREATE INDEX IF NOT EXISTS idx_songs_artist_title_id ON songs (artist, title, id);
+CREATE INDEX IF NOT EXISTS idx_songs_artist_created_at_id ON songs (artist, created_at, id);
+
+ALTER TABLE songs DROP COLUMN IF EXISTS artist_key;
@@ -0,0 +1,10 @@
+-- The artist part of identity_key, which the application computes with the
+-- same normalization as the artist cache keys.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS artist_key TEXT
+    GENERATED ALWAYS AS (split_part(identity_key, chr(31), 1)) STORED;
+
+DROP INDEX IF EXISTS idx_songs_artist_title_id;
+DROP INDEX IF EXISTS idx_songs_artist_created_at_id;
+
+CREATE INDEX IF NOT EXISTS idx_songs_artist_key_title_id ON songs (artist_key, title, id);
+CREATE INDEX IF NOT EXISTS idx_songs_artist_key_created_at_id ON songs (artist_key, created_at, id);
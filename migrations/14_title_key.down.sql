@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_songs_artist_key_title_key_id;
+
+CREATE INDEX IF NOT EXISTS idx_songs_artist_key_title_id ON songs (artist_key, title, id);
+
+ALTER TABLE songs DROP COLUMN IF EXISTS title_key;
@@ -0,0 +1,9 @@
+-- The title part of identity_key. Tracks of an artist are sorted by it in
+-- byte order, which the in-memory storage reproduces exactly, unlike the
+-- database collation.
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS title_key TEXT COLLATE "C"
+    GENERATED ALWAYS AS (split_part(identity_key, chr(31), 2)) STORED;
+
+DROP INDEX IF EXISTS idx_songs_artist_key_title_id;
+
+CREATE INDEX IF NOT EXISTS idx_songs_artist_key_title_key_id ON songs (artist_key, title_key, id);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_songs_artist_created_at_id;
+DROP INDEX IF EXISTS idx_songs_artist_title_id;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS created_at;
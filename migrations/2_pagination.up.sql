@@ -0,0 +1,4 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS created_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+CREATE INDEX IF NOT EXISTS idx_songs_artist_title_id ON songs (artist, title, id);
+CREATE INDEX IF NOT EXISTS idx_songs_artist_created_at_id ON songs (artist, created_at, id);
@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS idx_songs_search_vector;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+
+DROP FUNCTION IF EXISTS songs_search_document(TEXT[], TEXT[]);
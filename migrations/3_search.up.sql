@@ -0,0 +1,13 @@
+CREATE OR REPLACE FUNCTION songs_search_document(lyrics TEXT[], translation TEXT[])
+RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('simple', array_to_string(lyrics, ' ')), 'A') ||
+           setweight(to_tsvector('simple', array_to_string(translation, ' ')), 'B')
+$$;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (songs_search_document(lyrics, translation)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_songs_uuid;
+CREATE INDEX IF NOT EXISTS idx_songs_uuid ON songs (uuid);
+
+ALTER TABLE songs DROP COLUMN IF EXISTS updated_at;
@@ -0,0 +1,4 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS updated_at TIMESTAMPTZ NOT NULL DEFAULT now();
+
+DROP INDEX IF EXISTS idx_songs_uuid;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_uuid ON songs (uuid);
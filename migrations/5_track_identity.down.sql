@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_songs_identity_key;
+
+ALTER TABLE songs DROP COLUMN IF EXISTS identity_key;
+
+-- songs_identity_duplicates is kept, it holds the only copy of the songs
+-- removed as duplicates.
@@ -0,0 +1,33 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS identity_key TEXT;
+
+-- New rows get their key from the application (NFC, case folding, collapsed
+-- whitespace). The backfill only approximates it with lower(), cmd/migrator
+-- computes the exact keys in Go right after this migration.
+UPDATE songs SET identity_key =
+    btrim(regexp_replace(lower(normalize(artist, NFC)), '\s+', ' ', 'g')) || chr(31) ||
+    btrim(regexp_replace(lower(normalize(title, NFC)), '\s+', ' ', 'g'))
+WHERE identity_key IS NULL;
+
+-- Duplicates are moved aside with the id of the song that was kept, so they
+-- can be reviewed and merged back by hand.
+CREATE TABLE IF NOT EXISTS songs_identity_duplicates (LIKE songs);
+ALTER TABLE songs_identity_duplicates ADD COLUMN IF NOT EXISTS kept_id INT;
+
+WITH duplicates AS (
+    INSERT INTO songs_identity_duplicates
+    SELECT s.*, k.kept_id
+    FROM songs s
+    JOIN (
+        SELECT identity_key, min(id) AS kept_id
+        FROM songs
+        GROUP BY identity_key
+    ) k ON k.identity_key = s.identity_key
+    WHERE s.id > k.kept_id
+    RETURNING id
+)
+DELETE FROM songs
+WHERE id IN (SELECT id FROM duplicates);
+
+ALTER TABLE songs ALTER COLUMN identity_key SET NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_songs_identity_key ON songs (identity_key);
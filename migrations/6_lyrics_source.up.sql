@@ -0,0 +1,3 @@
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS lyrics_source VARCHAR(64) NOT NULL DEFAULT 'lyricsovh';
+
+ALTER TABLE songs ALTER COLUMN lyrics_source DROP DEFAULT;
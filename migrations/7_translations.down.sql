@@ -0,0 +1,27 @@
+DROP INDEX IF EXISTS idx_songs_search_vector;
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS translation TEXT[] NOT NULL DEFAULT '{}';
+
+UPDATE songs s SET translation = t.lines
+FROM translations t
+WHERE t.song_id = s.id AND t.lang = 'ru';
+
+ALTER TABLE songs ALTER COLUMN translation DROP DEFAULT;
+
+DROP TABLE IF EXISTS translations;
+DROP FUNCTION IF EXISTS lines_search_document(TEXT[], "char");
+
+CREATE OR REPLACE FUNCTION songs_search_document(lyrics TEXT[], translation TEXT[])
+RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('simple', array_to_string(lyrics, ' ')), 'A') ||
+           setweight(to_tsvector('simple', array_to_string(translation, ' ')), 'B')
+$$;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (songs_search_document(lyrics, translation)) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
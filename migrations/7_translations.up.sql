@@ -0,0 +1,34 @@
+CREATE OR REPLACE FUNCTION lines_search_document(lines TEXT[], weight "char")
+RETURNS tsvector
+LANGUAGE sql
+IMMUTABLE
+AS $$
+    SELECT setweight(to_tsvector('simple', array_to_string(lines, ' ')), weight)
+$$;
+
+CREATE TABLE IF NOT EXISTS translations
+(
+    song_id INT NOT NULL REFERENCES songs (id) ON DELETE CASCADE,
+    lang VARCHAR(16) NOT NULL,
+    lines TEXT[] NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    search_vector tsvector GENERATED ALWAYS AS (lines_search_document(lines, 'B')) STORED,
+    PRIMARY KEY (song_id, lang)
+);
+
+INSERT INTO translations (song_id, lang, lines)
+SELECT id, 'ru', translation FROM songs
+ON CONFLICT DO NOTHING;
+
+DROP INDEX IF EXISTS idx_songs_search_vector;
+ALTER TABLE songs DROP COLUMN IF EXISTS search_vector;
+DROP FUNCTION IF EXISTS songs_search_document(TEXT[], TEXT[]);
+
+ALTER TABLE songs DROP COLUMN IF EXISTS translation;
+
+ALTER TABLE songs ADD COLUMN IF NOT EXISTS search_vector tsvector
+    GENERATED ALWAYS AS (lines_search_document(lyrics, 'A')) STORED;
+
+CREATE INDEX IF NOT EXISTS idx_songs_search_vector ON songs USING GIN (search_vector);
+CREATE INDEX IF NOT EXISTS idx_translations_search_vector ON translations USING GIN (search_vector);
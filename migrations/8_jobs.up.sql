@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS jobs
+(
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    status VARCHAR(16) NOT NULL DEFAULT 'pending',
+    artist VARCHAR(255) NOT NULL,
+    title VARCHAR(255) NOT NULL,
+    target_langs TEXT[] NOT NULL DEFAULT '{}',
+    track_uuid UUID,
+    error TEXT NOT NULL DEFAULT '',
+    attempts INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS idx_jobs_status_created_at ON jobs (status, created_at);